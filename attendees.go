@@ -0,0 +1,82 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+const (
+	// attendeesNone leaves the attendees of the events alone.
+	attendeesNone = "none"
+	// attendeesInvite adds the co-travelers as attendees of the flight events.
+	attendeesInvite = "invite"
+	// attendeesDedupe skips the flight events a co-traveler's bot already
+	// invited the calendar to.
+	attendeesDedupe = "dedupe"
+)
+
+// attendeeEmails returns the email addresses of the travelers of the event
+// in the mapping of traveler names and profile refs to email addresses,
+// sorted and without self.
+func attendeeEmails(e tripit.Event, emails map[string]string, self string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, key := range append(append([]string{}, e.Travelers...), e.TravelerRefs...) {
+		email, ok := emails[normalizeAttendeeKey(key)]
+		if !ok || seen[email] || strings.EqualFold(email, self) {
+			continue
+		}
+		seen[email] = true
+		out = append(out, email)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// normalizeAttendeeKey normalizes a traveler name or profile ref, so the
+// keys in the config match the names in TripIt regardless of case and
+// spacing.
+func normalizeAttendeeKey(key string) string {
+	return strings.ToLower(strings.Join(strings.Fields(key), " "))
+}
+
+// addAttendees adds the email addresses that are not attendees of the event
+// yet, so the responses of the existing attendees are kept.
+func addAttendees(event *calendar.Event, emails []string) {
+	for _, email := range emails {
+		found := false
+		for _, a := range event.Attendees {
+			if strings.EqualFold(a.Email, email) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			event.Attendees = append(event.Attendees, &calendar.EventAttendee{Email: email})
+		}
+	}
+}
+
+// isInvitedDuplicate returns if the calendar already has an event for the
+// same flight that someone else organized, like the invitation from the bot
+// of a co-traveler.
+func isInvitedDuplicate(e tripit.Event, events []*calendar.Event) bool {
+	start, err := time.Parse(time.RFC3339, e.Start.DateTime)
+	if err != nil {
+		return false
+	}
+
+	for _, ev := range events {
+		if ev.Summary != e.Title || ev.Organizer == nil || ev.Organizer.Self || ev.Start == nil {
+			continue
+		}
+		if s, err := time.Parse(time.RFC3339, ev.Start.DateTime); err == nil && s.Equal(start) {
+			return true
+		}
+	}
+	return false
+}
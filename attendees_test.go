@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/calendartest"
+	"github.com/jessfraz/tripitcalb0t/tripit"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func TestSyncAttendees(t *testing.T) {
+	emails := map[string]string{
+		normalizeAttendeeKey("Alex Traveler"): "alex@example.com",
+		normalizeAttendeeKey("SAMREF"):        "sam@example.com",
+		// The user is a traveler too, but not their own attendee.
+		normalizeAttendeeKey("Jess Frazelle"): testCalendar,
+	}
+	// invitation returns the copy of the flight in the calendar that the
+	// organizer invited it to.
+	invitation := func(organizer *calendar.EventOrganizer, attendees ...*calendar.EventAttendee) calendar.Event {
+		e := testEvent("Flight UA 100")
+		e.Organizer = organizer
+		e.Attendees = attendees
+		return e
+	}
+
+	tests := []struct {
+		name      string
+		attendees string
+		// existing is the event in the calendar before the sync, if any.
+		existing *calendar.Event
+		// want are the attendees of the flight after the sync with their
+		// responses, of the event we synced or else of the existing one.
+		want []string
+		// flights is how many events the calendar has for the flight.
+		flights int
+	}{
+		{
+			name:      "none",
+			attendees: attendeesNone,
+			want:      []string{},
+			flights:   1,
+		},
+		{
+			name:      "invite",
+			attendees: attendeesInvite,
+			want:      []string{"alex@example.com:", "sam@example.com:"},
+			flights:   1,
+		},
+		{
+			name:      "invite keeps the responses",
+			attendees: attendeesInvite,
+			existing: func() *calendar.Event {
+				e := invitation(&calendar.EventOrganizer{Email: testCalendar, Self: true},
+					&calendar.EventAttendee{Email: "Alex@example.com", ResponseStatus: "accepted"},
+					&calendar.EventAttendee{Email: "kim@example.com", ResponseStatus: "tentative"})
+				e.Description = "segment0"
+				return &e
+			}(),
+			want:    []string{"Alex@example.com:accepted", "kim@example.com:tentative", "sam@example.com:"},
+			flights: 1,
+		},
+		{
+			name:      "dedupe an invitation from a co-traveler",
+			attendees: attendeesDedupe,
+			existing: func() *calendar.Event {
+				e := invitation(&calendar.EventOrganizer{Email: "alex@example.com"},
+					&calendar.EventAttendee{Email: testCalendar, Self: true})
+				return &e
+			}(),
+			want:    []string{testCalendar + ":"},
+			flights: 1,
+		},
+		{
+			name:      "dedupe keeps the events the user organized",
+			attendees: attendeesDedupe,
+			existing: func() *calendar.Event {
+				e := invitation(&calendar.EventOrganizer{Email: testCalendar, Self: true})
+				return &e
+			}(),
+			// The event of the user and the one we synced.
+			want:    []string{},
+			flights: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := calendartest.NewServer()
+			defer cs.Close()
+
+			ctx := context.Background()
+			if tt.existing != nil {
+				cs.AddEvent(testCalendar, *tt.existing)
+			}
+
+			target := testTarget(t, calendarTarget{
+				Calendar:  testCalendar,
+				Attendees: tt.attendees,
+				Kinds:     []tripit.EventKind{tripit.EventKindFlight, tripit.EventKindBuffer},
+			})
+			flight := testFlights(1)[0]
+			flight.Travelers = []string{"Jess Frazelle", "alex  traveler"}
+			flight.TravelerRefs = []string{"SAMREF"}
+			buffer := flight
+			buffer.Kind, buffer.Title, buffer.Key = tripit.EventKindBuffer, "Buffer for travel time to SFO", "segment0-to"
+			events := target.events([]tripit.Event{flight, buffer}, time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+			if _, err := syncEvents(ctx, ctx, testWriter(cs), target, emails, events, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			var flights int
+			var synced, existing *calendar.Event
+			for _, e := range cs.Events(testCalendar) {
+				switch {
+				case e.Summary == buffer.Title:
+					if len(e.Attendees) > 0 {
+						t.Errorf("buffer has the attendees %v, want none", e.Attendees)
+					}
+				case e.Summary != flight.Title:
+					t.Errorf("calendar has %q", e.Summary)
+				case eventKey(e) != "":
+					flights++
+					synced = e
+				default:
+					flights++
+					existing = e
+				}
+			}
+			if flights != tt.flights {
+				t.Errorf("calendar has %d events for the flight, want %d", flights, tt.flights)
+			}
+			if synced == nil {
+				synced = existing
+			}
+			if synced == nil {
+				t.Fatal("calendar has no event for the flight")
+			}
+
+			got := []string{}
+			for _, a := range synced.Attendees {
+				got = append(got, a.Email+":"+a.ResponseStatus)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("attendees are %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
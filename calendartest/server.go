@@ -0,0 +1,579 @@
+// Package calendartest provides a fake of the Google Calendar v3 events API
+// for tests, so the sync can run against it together with the fake of the
+// TripIt API in tripit/tripittest without the network.
+package calendartest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+const (
+	// DefaultMaxResults is the page size of the list if the request has no
+	// maxResults, like the real API.
+	DefaultMaxResults = 250
+	// MaxMaxResults is the largest page size the list returns.
+	MaxMaxResults = 2500
+)
+
+// eventColors is the event palette of the real API.
+var eventColors = map[string]calendar.ColorDefinition{
+	"1":  {Background: "#a4bdfc", Foreground: "#1d1d1d"},
+	"2":  {Background: "#7ae7bf", Foreground: "#1d1d1d"},
+	"3":  {Background: "#dbadff", Foreground: "#1d1d1d"},
+	"4":  {Background: "#ff887c", Foreground: "#1d1d1d"},
+	"5":  {Background: "#fbd75b", Foreground: "#1d1d1d"},
+	"6":  {Background: "#ffb878", Foreground: "#1d1d1d"},
+	"7":  {Background: "#46d6db", Foreground: "#1d1d1d"},
+	"8":  {Background: "#e1e1e1", Foreground: "#1d1d1d"},
+	"9":  {Background: "#5484ed", Foreground: "#1d1d1d"},
+	"10": {Background: "#51b749", Foreground: "#1d1d1d"},
+	"11": {Background: "#dc2127", Foreground: "#1d1d1d"},
+}
+
+// Server is a fake of the Google Calendar v3 events API. It keeps the
+// events of any number of calendars in memory, a calendar exists once it
+// is used.
+type Server struct {
+	*httptest.Server
+
+	// Now returns the time used for the created and updated fields, it
+	// defaults to time.Now.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	calendars map[string][]*calendar.Event
+	nextID    int
+	faults    []*fault
+	requests  []Request
+}
+
+// Request is a request the Server received.
+type Request struct {
+	// Operation is one of get, list, insert, update, patch or delete for the
+	// events, calendar for the calendars and colors for the colors.
+	Operation string
+	Calendar  string
+	EventID   string
+}
+
+// fault is an injected failure of the requests for an operation.
+type fault struct {
+	operation  string
+	statusCode int
+	remaining  int
+	// after fails the request after it is done.
+	after bool
+}
+
+// NewServer starts a Server without events. The caller should call Close
+// when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		Now:       time.Now,
+		calendars: map[string][]*calendar.Event{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Service returns a calendar.Service that talks to the server.
+func (s *Server) Service() *calendar.Service {
+	// calendar.New only fails for a nil client.
+	svc, _ := calendar.New(s.Client())
+	svc.BasePath = s.URL + "/calendar/v3/"
+	return svc
+}
+
+// AddEvent adds the event to the calendar as if a user created it, and
+// returns it with its ID.
+func (s *Server) AddEvent(calendarID string, event calendar.Event) *calendar.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insert(calendarID, &event)
+}
+
+// Events returns the events of the calendar that are not deleted, in the
+// order they were created.
+func (s *Server) Events(calendarID string) []*calendar.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []*calendar.Event
+	for _, e := range s.calendars[calendarID] {
+		if e.Status != "cancelled" {
+			events = append(events, copyEvent(e))
+		}
+	}
+	return events
+}
+
+// Dump returns the events of the calendar that are not deleted as indented
+// JSON, without the fields the server generates. The output is stable so it
+// can be compared to a golden file.
+func (s *Server) Dump(calendarID string) ([]byte, error) {
+	events := s.Events(calendarID)
+	for _, e := range events {
+		e.Id, e.Etag, e.HtmlLink, e.ICalUID, e.Created, e.Updated = "", "", "", "", "", ""
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return startTime(events[i]) < startTime(events[j])
+	})
+
+	b, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// Fail makes the next n requests for the operation fail with the status
+// code, the operations are the ones of Request. A negative n fails them
+// until Recover is called.
+func (s *Server) Fail(operation string, statusCode, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &fault{
+		operation:  operation,
+		statusCode: statusCode,
+		remaining:  n,
+	})
+}
+
+// FailAfter is like Fail, but the requests are done before they fail, like
+// when the response is lost on its way back.
+func (s *Server) FailAfter(operation string, statusCode, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &fault{
+		operation:  operation,
+		statusCode: statusCode,
+		remaining:  n,
+		after:      true,
+	})
+}
+
+// Recover removes the faults injected with Fail and FailAfter.
+func (s *Server) Recover() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Requests returns the requests the server received, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The paths are /calendar/v3/calendars/<calendar>[/events[/<event>]]
+	// and /calendar/v3/colors.
+	var parts []string
+	for _, p := range strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/") {
+		p, err := url.PathUnescape(p)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "badRequest", err.Error())
+			return
+		}
+		parts = append(parts, p)
+	}
+	if len(parts) < 3 || parts[0] != "calendar" || parts[1] != "v3" {
+		writeError(w, http.StatusNotFound, "notFound", "Not Found")
+		return
+	}
+
+	var req Request
+	switch {
+	case len(parts) == 3 && parts[2] == "colors" && r.Method == http.MethodGet:
+		req.Operation = "colors"
+	case len(parts) < 4 || parts[2] != "calendars":
+	case len(parts) == 4 && r.Method == http.MethodGet:
+		req.Calendar = parts[3]
+		req.Operation = "calendar"
+	case len(parts) == 4 && r.Method == http.MethodGet:
+		req.Operation = "calendar"
+	case len(parts) == 5 && parts[4] == "events" && r.Method == http.MethodGet:
+		req.Calendar = parts[3]
+		req.Operation = "list"
+	case len(parts) == 5 && parts[4] == "events" && r.Method == http.MethodPost:
+		req.Calendar = parts[3]
+		req.Operation = "insert"
+	case len(parts) == 6 && parts[4] == "events":
+		req.Calendar = parts[3]
+		req.EventID = parts[5]
+		switch r.Method {
+		case http.MethodGet:
+			req.Operation = "get"
+		case http.MethodPut:
+			req.Operation = "update"
+		case http.MethodPatch:
+			req.Operation = "patch"
+		case http.MethodDelete:
+			req.Operation = "delete"
+		}
+	}
+	if req.Operation == "" {
+		writeError(w, http.StatusNotFound, "notFound", "Not Found")
+		return
+	}
+	s.requests = append(s.requests, req)
+
+	for _, f := range s.faults {
+		if f.remaining == 0 || f.operation != req.Operation {
+			continue
+		}
+		f.remaining--
+		if f.after {
+			s.serve(httptest.NewRecorder(), r, req)
+		}
+		writeError(w, f.statusCode, "backendError", http.StatusText(f.statusCode))
+		return
+	}
+
+	s.serve(w, r, req)
+}
+
+// serve serves the request for the operation.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, req Request) {
+	switch req.Operation {
+	case "colors":
+		writeJSON(w, http.StatusOK, &calendar.Colors{
+			Kind:  "calendar#colors",
+			Event: eventColors,
+		})
+	case "calendar":
+		writeJSON(w, http.StatusOK, &calendar.Calendar{
+			Kind:     "calendar#calendar",
+			Id:       req.Calendar,
+			Summary:  req.Calendar,
+			TimeZone: "UTC",
+		})
+	case "list":
+		s.list(w, r, req.Calendar)
+	case "insert":
+		var event calendar.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			writeError(w, http.StatusBadRequest, "parseError", err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, s.insert(req.Calendar, &event))
+	default:
+		s.event(w, r, req)
+	}
+}
+
+// list serves the list of the events with the filters the sync uses.
+func (s *Server) list(w http.ResponseWriter, r *http.Request, calendarID string) {
+	q := r.URL.Query()
+
+	maxResults := DefaultMaxResults
+	if v := q.Get("maxResults"); v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 1 {
+			writeError(w, http.StatusBadRequest, "invalid", fmt.Sprintf("Invalid value for maxResults: %s", v))
+			return
+		}
+		maxResults = i
+	}
+	if maxResults > MaxMaxResults {
+		maxResults = MaxMaxResults
+	}
+
+	offset := 0
+	if v := q.Get("pageToken"); v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 0 {
+			writeError(w, http.StatusBadRequest, "invalid", "Invalid page token")
+			return
+		}
+		offset = i
+	}
+
+	var events []*calendar.Event
+	for _, e := range s.calendars[calendarID] {
+		if e.Status == "cancelled" && q.Get("showDeleted") != "true" {
+			continue
+		}
+		if v := q.Get("q"); v != "" && !matchesQuery(e, v) {
+			continue
+		}
+		if v := q.Get("timeMin"); v != "" && endTime(e) != "" && endTime(e) <= toUTC(v) {
+			continue
+		}
+		if v := q.Get("timeMax"); v != "" && startTime(e) >= toUTC(v) {
+			continue
+		}
+		if !hasProperties(e, q["privateExtendedProperty"], true) || !hasProperties(e, q["sharedExtendedProperty"], false) {
+			continue
+		}
+		events = append(events, e)
+	}
+
+	switch q.Get("orderBy") {
+	case "updated":
+		sort.SliceStable(events, func(i, j int) bool { return events[i].Updated < events[j].Updated })
+	case "startTime":
+		sort.SliceStable(events, func(i, j int) bool { return startTime(events[i]) < startTime(events[j]) })
+	}
+
+	resp := &calendar.Events{
+		Kind:    "calendar#events",
+		Summary: calendarID,
+		Updated: s.timestamp(),
+	}
+	for i := offset; i < len(events) && i < offset+maxResults; i++ {
+		resp.Items = append(resp.Items, events[i])
+	}
+	if offset+maxResults < len(events) {
+		resp.NextPageToken = strconv.Itoa(offset + maxResults)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// event serves the operations on a single event.
+func (s *Server) event(w http.ResponseWriter, r *http.Request, req Request) {
+	var event *calendar.Event
+	for _, e := range s.calendars[req.Calendar] {
+		if e.Id == req.EventID {
+			event = e
+		}
+	}
+	if event == nil {
+		writeError(w, http.StatusNotFound, "notFound", "Not Found")
+		return
+	}
+	// The deleted events can still be read, but not changed.
+	if event.Status == "cancelled" && req.Operation != "get" {
+		writeError(w, http.StatusGone, "deleted", "Resource has been deleted")
+		return
+	}
+
+	switch req.Operation {
+	case "get":
+		writeJSON(w, http.StatusOK, event)
+	case "update":
+		var update calendar.Event
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			writeError(w, http.StatusBadRequest, "parseError", err.Error())
+			return
+		}
+		update.Id, update.ICalUID, update.HtmlLink, update.Created = event.Id, event.ICalUID, event.HtmlLink, event.Created
+		if update.Status == "" {
+			update.Status = "confirmed"
+		}
+		*event = update
+		s.touch(event)
+		writeJSON(w, http.StatusOK, event)
+	case "patch":
+		var patch map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			writeError(w, http.StatusBadRequest, "parseError", err.Error())
+			return
+		}
+		patched, err := patchEvent(event, patch)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid", err.Error())
+			return
+		}
+		*event = *patched
+		s.touch(event)
+		writeJSON(w, http.StatusOK, event)
+	case "delete":
+		event.Status = "cancelled"
+		s.touch(event)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) insert(calendarID string, event *calendar.Event) *calendar.Event {
+	s.nextID++
+	e := copyEvent(event)
+	e.Kind = "calendar#event"
+	e.Id = fmt.Sprintf("event%d", s.nextID)
+	e.ICalUID = e.Id + "@google.com"
+	e.HtmlLink = "https://www.google.com/calendar/event?eid=" + e.Id
+	e.Created = s.timestamp()
+	if e.Status == "" {
+		e.Status = "confirmed"
+	}
+	s.touch(e)
+
+	s.calendars[calendarID] = append(s.calendars[calendarID], e)
+	return copyEvent(e)
+}
+
+// touch sets the updated time and a new etag of the event.
+func (s *Server) touch(e *calendar.Event) {
+	e.Updated = s.timestamp()
+	e.Etag = strconv.Quote(strconv.FormatInt(s.Now().UnixNano(), 10))
+	e.Sequence++
+}
+
+func (s *Server) timestamp() string {
+	return s.Now().UTC().Format(time.RFC3339Nano)
+}
+
+// patchEvent returns the event with the fields in the patch, the nested
+// objects are merged and a null clears a field.
+func patchEvent(event *calendar.Event, patch map[string]interface{}) (*calendar.Event, error) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	var current map[string]interface{}
+	if err := json.Unmarshal(b, &current); err != nil {
+		return nil, err
+	}
+
+	// The ids can not be patched.
+	for _, k := range []string{"id", "iCalUID", "htmlLink", "created"} {
+		delete(patch, k)
+	}
+	mergePatch(current, patch)
+
+	if b, err = json.Marshal(current); err != nil {
+		return nil, err
+	}
+	var patched calendar.Event
+	if err := json.Unmarshal(b, &patched); err != nil {
+		return nil, err
+	}
+	return &patched, nil
+}
+
+func mergePatch(dst, patch map[string]interface{}) {
+	for k, v := range patch {
+		if v == nil {
+			delete(dst, k)
+			continue
+		}
+
+		p, ok := v.(map[string]interface{})
+		d, isMap := dst[k].(map[string]interface{})
+		if ok && isMap {
+			mergePatch(d, p)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+// matchesQuery returns if the free text search q matches the event, like
+// the API it searches the summary, description and location.
+func matchesQuery(e *calendar.Event, q string) bool {
+	text := strings.ToLower(strings.Join([]string{e.Summary, e.Description, e.Location}, " "))
+	for _, word := range strings.Fields(strings.ToLower(q)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasProperties returns if the event has all the extended properties, they
+// are in the form name=value.
+func hasProperties(e *calendar.Event, properties []string, private bool) bool {
+	for _, p := range properties {
+		name, value := p, ""
+		if i := strings.Index(p, "="); i >= 0 {
+			name, value = p[:i], p[i+1:]
+		}
+
+		var m map[string]string
+		if e.ExtendedProperties != nil {
+			m = e.ExtendedProperties.Shared
+			if private {
+				m = e.ExtendedProperties.Private
+			}
+		}
+		if v, ok := m[name]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// startTime returns the start of the event in UTC, so the times sort.
+func startTime(e *calendar.Event) string {
+	return utc(e.Start)
+}
+
+// endTime returns the end of the event in UTC, so the times sort.
+func endTime(e *calendar.Event) string {
+	return utc(e.End)
+}
+
+func utc(d *calendar.EventDateTime) string {
+	if d == nil {
+		return ""
+	}
+	if d.DateTime == "" {
+		return d.Date
+	}
+	return toUTC(d.DateTime)
+}
+
+func toUTC(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// copyEvent returns a deep copy of the event, so the callers can not change
+// the events on the server.
+func copyEvent(e *calendar.Event) *calendar.Event {
+	b, err := json.Marshal(e)
+	if err != nil {
+		panic(fmt.Sprintf("encoding event failed: %v", err))
+	}
+	var c calendar.Event
+	if err := json.Unmarshal(b, &c); err != nil {
+		panic(fmt.Sprintf("decoding event failed: %v", err))
+	}
+	return &c
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in the format of the Google APIs, so the
+// client returns a *googleapi.Error.
+func writeError(w http.ResponseWriter, statusCode int, reason, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    statusCode,
+			"message": message,
+			"errors": []map[string]string{{
+				"domain":  "global",
+				"reason":  reason,
+				"message": message,
+			}},
+		},
+	})
+}
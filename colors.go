@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+const (
+	statusCancelled = "cancelled"
+	statusDelayed   = "delayed"
+	statusDiverted  = "diverted"
+	statusOnTime    = "on_time"
+)
+
+// colorNames maps the names Google calendar shows for the event colors to
+// their IDs, the colors API only has the IDs.
+var colorNames = map[string]string{
+	"lavender":  "1",
+	"sage":      "2",
+	"grape":     "3",
+	"flamingo":  "4",
+	"banana":    "5",
+	"tangerine": "6",
+	"peacock":   "7",
+	"graphite":  "8",
+	"blueberry": "9",
+	"basil":     "10",
+	"tomato":    "11",
+}
+
+// colorID returns the ID of the color, which can be its name or ID.
+func colorID(color string) string {
+	if id, ok := colorNames[strings.ToLower(strings.TrimSpace(color))]; ok {
+		return id
+	}
+	return strings.TrimSpace(color)
+}
+
+func validateColorID(id string) error {
+	if _, err := strconv.Atoi(id); err != nil {
+		return fmt.Errorf("unknown color %q, must be a color ID or one of %s", id, strings.Join(sortedColorNames(), ", "))
+	}
+	return nil
+}
+
+func sortedColorNames() []string {
+	names := make([]string, 0, len(colorNames))
+	for name := range colorNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// flightStatus returns the name of the group of the flight status code, or
+// an empty string if the flight is not monitored.
+func flightStatus(code tripit.FlightStatusCode) string {
+	switch code {
+	case tripit.FlightStatusCancelled:
+		return statusCancelled
+	case tripit.FlightStatusDelayed, tripit.FlightStatusInFlightLate, tripit.FlightStatusArrivedLate,
+		tripit.FlightStatusPossiblyDelayed, tripit.FlightStatusInFlightPossiblyLate, tripit.FlightStatusArrivedPossiblyLate:
+		return statusDelayed
+	case tripit.FlightStatusDiverted:
+		return statusDiverted
+	case tripit.FlightStatusOnTime, tripit.FlightStatusInFlightOnTime, tripit.FlightStatusArrivedOnTime:
+		return statusOnTime
+	}
+	return ""
+}
+
+func isFlightStatus(status string) bool {
+	switch status {
+	case statusCancelled, statusDelayed, statusDiverted, statusOnTime:
+		return true
+	}
+	return false
+}
+
+// resolveColors replaces the color names in the mapping with their IDs.
+func resolveColors(colors map[string]string) map[string]string {
+	if colors == nil {
+		return nil
+	}
+	out := make(map[string]string, len(colors))
+	for key, color := range colors {
+		out[key] = colorID(color)
+	}
+	return out
+}
+
+// eventColor returns the color ID of the event. The flight status wins over
+// the trip, the trip over the airline and the airline over the kind of the
+// event, so a cancelled flight stands out whatever else is configured. The
+// airline colors are only for the flights, not the buffers around them.
+func (t calendarTarget) eventColor(e tripit.Event) string {
+	if color, ok := t.StatusColors[flightStatus(e.Status)]; ok {
+		return color
+	}
+	if color, ok := t.TripColors[e.ID]; ok {
+		return color
+	}
+	if color, ok := t.AirlineColors[strings.ToUpper(e.AirlineCode)]; ok && e.Kind == tripit.EventKindFlight {
+		return color
+	}
+	if color, ok := t.Colors[e.Kind]; ok {
+		return color
+	}
+	return e.ColorID
+}
+
+// colorIDs returns all the color IDs the target uses.
+func (t calendarTarget) colorIDs() []string {
+	var ids []string
+	for _, colors := range []map[string]string{t.StatusColors, t.TripColors, t.AirlineColors} {
+		for _, id := range colors {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range t.Colors {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// checkColors checks that the color IDs of the calendars of the account are
+// in the event palette of the calendar API.
+func checkColors(ctx context.Context, gcalClient *calendar.Service, targets []calendarTarget) error {
+	var ids []string
+	for _, t := range targets {
+		ids = append(ids, t.colorIDs()...)
+	}
+	if len(ids) < 1 {
+		return nil
+	}
+
+	colors, err := gcalClient.Colors.Get().Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("getting the calendar colors failed: %v", err)
+	}
+
+	for _, id := range ids {
+		if _, ok := colors.Event[id]; !ok {
+			return fmt.Errorf("color ID %q is not in the event colors of the calendar", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,544 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+	calendar "google.golang.org/api/calendar/v3"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// defaultConfigFile is the name of the config file in the credentials directory.
+const defaultConfigFile = "config.yml"
+
+// config holds the configuration for syncing one or more TripIt accounts.
+//
+//	accounts:
+//	  - name: jess
+//	    tripit_username: jess@example.com
+//	    tripit_password: secret
+//	    interval: 5m
+//	    calendars:
+//	      - calendar: jess@example.com
+//	        past: true
+//	        colors:
+//	          flight: blueberry
+//	        status_colors:
+//	          cancelled: tomato
+//	        attendees: invite
+//	      - calendar: work@example.com
+//	        buffers: false
+//	        privacy: redacted
+//	      - calendar: team@example.com
+//	        kinds: [trip]
+//	        privacy: team
+//	        prune: true
+//	    attendees:
+//	      Alex Traveler: alex@example.com
+//	privacy_profiles:
+//	  team:
+//	    fields: [route, arrival]
+//	    visibility: public
+type config struct {
+	// PrivacyProfiles are the privacy profiles the calendars can use
+	// besides the built in full and redacted ones.
+	PrivacyProfiles map[string]privacyProfile `yaml:"privacy_profiles"`
+
+	Accounts []accountConfig `yaml:"accounts"`
+}
+
+// accountConfig holds the configuration for a TripIt account.
+type accountConfig struct {
+	// Name is used in the logs, it defaults to the TripIt username.
+	Name           string `yaml:"name"`
+	TripItUsername string `yaml:"tripit_username"`
+	TripItPassword string `yaml:"tripit_password"`
+	// The password can also be read from a file, a command or a keyring
+	// instead of being written in the config file.
+	TripItPasswordFile    string `yaml:"tripit_password_file"`
+	TripItPasswordCommand string `yaml:"tripit_password_command"`
+	TripItPasswordKeyring string `yaml:"tripit_password_keyring"`
+
+	// GoogleKeyfile, GoogleToken and GoogleSubject default to the
+	// --google-keyfile, --google-token-file and --google-subject flags.
+	GoogleKeyfile string `yaml:"google_keyfile"`
+	GoogleToken   string `yaml:"google_token_file"`
+	GoogleSubject string `yaml:"google_subject"`
+
+	// Interval defaults to the --interval flag.
+	Interval time.Duration `yaml:"interval"`
+	// Concurrency is how many writes to a calendar are in flight at the
+	// same time, it defaults to 4.
+	Concurrency int `yaml:"concurrency"`
+	// Timezone is the home time zone, it defaults to Local.
+	Timezone     string `yaml:"timezone"`
+	AirportsFile string `yaml:"airports_file"`
+
+	// Attendees maps the names of travelers and TripIt profile refs to
+	// their email addresses, for the attendees of the flight events.
+	Attendees map[string]string `yaml:"attendees"`
+
+	Calendars []calendarTarget `yaml:"calendars"`
+}
+
+// calendarTarget holds the configuration for a calendar the events of an
+// account are synced to.
+type calendarTarget struct {
+	Calendar string `yaml:"calendar"`
+	// Past includes the events of past trips.
+	Past bool `yaml:"past"`
+	// Buffers includes the buffer events for the travel time to and from the
+	// airport, it defaults to true.
+	Buffers *bool `yaml:"buffers"`
+	// ObjectTypes are the TripIt object types to create events for, it
+	// defaults to all of them.
+	ObjectTypes []tripit.Type `yaml:"object_types"`
+	// Kinds are the kinds of events (flight, buffer, trip, lodging, car,
+	// rail, activity) synced to the calendar, it defaults to the flights
+	// and their buffers.
+	Kinds []tripit.EventKind `yaml:"kinds"`
+	// Prune deletes the events synced to the calendar before that are not
+	// in TripIt or do not match the rules of the calendar anymore. Only the
+	// events that have not ended are deleted.
+	Prune bool `yaml:"prune"`
+	// Colors overrides the color of the events by their kind, the colors
+	// can be names (tomato, basil, ...) or IDs.
+	Colors map[tripit.EventKind]string `yaml:"colors"`
+	// AirlineColors overrides the color of the events by airline code.
+	AirlineColors map[string]string `yaml:"airline_colors"`
+	// TripColors overrides the color of the events by TripIt trip ID.
+	TripColors map[string]string `yaml:"trip_colors"`
+	// StatusColors overrides the color of the events by flight status
+	// (cancelled, delayed, diverted, on_time).
+	StatusColors map[string]string `yaml:"status_colors"`
+	// Reminders overrides the default reminders of the calendar for the
+	// events by their kind.
+	Reminders map[tripit.EventKind][]reminderRule `yaml:"reminders"`
+	// Shared is how to handle the trips shared with the account that it is
+	// not traveling on (none, skip, prefix, only), it defaults to none,
+	// which only lists the trips the account is traveling on.
+	Shared string `yaml:"shared"`
+	// Attendees is how to handle the co-travelers on the flights (none,
+	// invite, dedupe), it defaults to none.
+	Attendees string `yaml:"attendees"`
+	// Privacy is the name of the privacy profile of the events, it
+	// defaults to full.
+	Privacy string `yaml:"privacy"`
+
+	// privacy is the privacy profile named by Privacy.
+	privacy privacyProfile
+	// owner is the TripIt username of the account, the events are marked
+	// with it.
+	owner string
+}
+
+// loadConfig reads the config file, sets the defaults and validates it.
+func loadConfig(file string, defaultInterval time.Duration) (*config, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file %s failed: %v", file, err)
+	}
+
+	var c config
+	if err := yaml.UnmarshalStrict(b, &c); err != nil {
+		return nil, fmt.Errorf("parsing config file %s failed: %v", file, err)
+	}
+
+	if len(c.Accounts) < 1 {
+		return nil, fmt.Errorf("config file %s has no accounts", file)
+	}
+
+	if err := validatePrivacyProfiles(c.PrivacyProfiles); err != nil {
+		return nil, fmt.Errorf("config file %s: %v", file, err)
+	}
+
+	for i := range c.Accounts {
+		a := &c.Accounts[i]
+		a.setDefaults(defaultInterval)
+		if err := a.validate(); err != nil {
+			return nil, fmt.Errorf("config file %s: account %q: %v", file, a.Name, err)
+		}
+		if err := a.setPrivacyProfiles(c.PrivacyProfiles); err != nil {
+			return nil, fmt.Errorf("config file %s: account %q: %v", file, a.Name, err)
+		}
+	}
+
+	return &c, nil
+}
+
+// defaultConfigPath returns the path of the config file in the credentials
+// directory if it exists.
+func defaultConfigPath() string {
+	file := filepath.Join(credsDir, defaultConfigFile)
+	if _, err := os.Stat(file); err != nil {
+		return ""
+	}
+	return file
+}
+
+func (a *accountConfig) setDefaults(defaultInterval time.Duration) {
+	if a.Name == "" {
+		a.Name = a.TripItUsername
+	}
+	if a.GoogleKeyfile == "" {
+		a.GoogleKeyfile = googleCalendarKeyfile
+	}
+	if a.GoogleToken == "" {
+		a.GoogleToken = googleTokenFile
+	}
+	if a.GoogleSubject == "" {
+		a.GoogleSubject = googleSubject
+	}
+	if a.Interval == 0 {
+		a.Interval = defaultInterval
+	}
+	if a.Timezone == "" {
+		a.Timezone = "Local"
+	}
+	if a.Concurrency == 0 {
+		a.Concurrency = defaultWriteConcurrency
+	}
+
+	attendees := make(map[string]string, len(a.Attendees))
+	for key, email := range a.Attendees {
+		attendees[normalizeAttendeeKey(key)] = email
+	}
+	a.Attendees = attendees
+
+	for i := range a.Calendars {
+		t := &a.Calendars[i]
+		if t.Buffers == nil {
+			buffers := true
+			t.Buffers = &buffers
+		}
+		if t.Shared == "" {
+			t.Shared = sharedNone
+		}
+		if t.Attendees == "" {
+			t.Attendees = attendeesNone
+		}
+		if t.Privacy == "" {
+			t.Privacy = privacyFull
+		}
+		if len(t.Kinds) == 0 {
+			t.Kinds = defaultKinds()
+		}
+		t.owner = a.TripItUsername
+		for kind, color := range t.Colors {
+			t.Colors[kind] = colorID(color)
+		}
+		t.TripColors = resolveColors(t.TripColors)
+		t.StatusColors = resolveColors(t.StatusColors)
+		if t.AirlineColors != nil {
+			airlineColors := make(map[string]string, len(t.AirlineColors))
+			for code, color := range t.AirlineColors {
+				airlineColors[strings.ToUpper(code)] = colorID(color)
+			}
+			t.AirlineColors = airlineColors
+		}
+		for _, rules := range t.Reminders {
+			for j := range rules {
+				if rules[j].Method == "" {
+					rules[j].Method = reminderPopup
+				}
+			}
+		}
+	}
+}
+
+func (a *accountConfig) validate() error {
+	if len(a.TripItUsername) < 1 {
+		return errors.New("tripit username cannot be empty")
+	}
+
+	secrets := secretSources{
+		File:    a.TripItPasswordFile,
+		Command: a.TripItPasswordCommand,
+		Keyring: a.TripItPasswordKeyring,
+	}
+	if !secrets.isZero() {
+		password, err := secrets.resolve(a.TripItUsername)
+		if err != nil {
+			return err
+		}
+		a.TripItPassword = password
+	}
+
+	if len(a.TripItPassword) < 1 {
+		return errors.New("tripit password cannot be empty")
+	}
+
+	if _, err := os.Stat(a.GoogleKeyfile); os.IsNotExist(err) {
+		return fmt.Errorf("google calendar keyfile %q does not exist", a.GoogleKeyfile)
+	}
+
+	if a.Interval < 0 {
+		return errors.New("interval cannot be negative")
+	}
+
+	if a.Concurrency < 1 || a.Concurrency > maxWriteConcurrency {
+		return fmt.Errorf("concurrency must be between 1 and %d", maxWriteConcurrency)
+	}
+
+	if _, err := time.LoadLocation(a.Timezone); err != nil {
+		return fmt.Errorf("loading time zone %q failed: %v", a.Timezone, err)
+	}
+
+	if len(a.AirportsFile) > 0 {
+		if _, err := os.Stat(a.AirportsFile); os.IsNotExist(err) {
+			return fmt.Errorf("airports file %q does not exist", a.AirportsFile)
+		}
+	}
+
+	for key, email := range a.Attendees {
+		if !strings.Contains(email, "@") {
+			return fmt.Errorf("attendee %q has an invalid email address %q", key, email)
+		}
+	}
+
+	if len(a.Calendars) < 1 {
+		return errors.New("must have at least one calendar")
+	}
+
+	// The calendars of an account would delete each other's events.
+	seen := map[string]bool{}
+	for _, t := range a.Calendars {
+		if err := t.validate(); err != nil {
+			return fmt.Errorf("calendar %q: %v", t.Calendar, err)
+		}
+		if seen[t.Calendar] {
+			return fmt.Errorf("calendar %q is listed more than once", t.Calendar)
+		}
+		seen[t.Calendar] = true
+	}
+
+	return nil
+}
+
+func (t calendarTarget) validate() error {
+	if len(t.Calendar) < 1 {
+		return errors.New("calendar name cannot be empty")
+	}
+
+	switch t.Shared {
+	case sharedNone, sharedSkip, sharedPrefix, sharedOnly:
+	default:
+		return fmt.Errorf("unknown shared mode %q, must be one of %s, %s, %s, %s", t.Shared, sharedNone, sharedSkip, sharedPrefix, sharedOnly)
+	}
+
+	switch t.Attendees {
+	case attendeesNone, attendeesInvite, attendeesDedupe:
+	default:
+		return fmt.Errorf("unknown attendees mode %q, must be one of %s, %s, %s", t.Attendees, attendeesNone, attendeesInvite, attendeesDedupe)
+	}
+
+	for _, typ := range t.ObjectTypes {
+		if !isEventObjectType(typ) {
+			return fmt.Errorf("unsupported object type %q, only %s are synced", typ, joinTypes(eventObjectTypes()))
+		}
+	}
+
+	for _, kind := range t.Kinds {
+		if !isEventKind(kind) {
+			return fmt.Errorf("unknown event kind %q in kinds", kind)
+		}
+	}
+
+	for kind, color := range t.Colors {
+		if !isEventKind(kind) {
+			return fmt.Errorf("unknown event kind %q in colors", kind)
+		}
+		if err := validateColorID(color); err != nil {
+			return fmt.Errorf("%s color: %v", kind, err)
+		}
+	}
+	for code, color := range t.AirlineColors {
+		if err := validateColorID(color); err != nil {
+			return fmt.Errorf("airline %s color: %v", code, err)
+		}
+	}
+	for id, color := range t.TripColors {
+		if err := validateColorID(color); err != nil {
+			return fmt.Errorf("trip %s color: %v", id, err)
+		}
+	}
+	for status, color := range t.StatusColors {
+		if !isFlightStatus(status) {
+			return fmt.Errorf("unknown flight status %q in status colors, must be one of %s, %s, %s, %s", status, statusCancelled, statusDelayed, statusDiverted, statusOnTime)
+		}
+		if err := validateColorID(color); err != nil {
+			return fmt.Errorf("%s color: %v", status, err)
+		}
+	}
+
+	if err := validateReminders(t.Reminders); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// setPrivacyProfiles looks up the privacy profiles of the calendars.
+func (a *accountConfig) setPrivacyProfiles(profiles map[string]privacyProfile) error {
+	for i := range a.Calendars {
+		t := &a.Calendars[i]
+		p, ok := builtinPrivacyProfiles[t.Privacy]
+		if !ok {
+			p, ok = profiles[t.Privacy]
+		}
+		if !ok {
+			return fmt.Errorf("calendar %q: unknown privacy profile %q", t.Calendar, t.Privacy)
+		}
+		t.privacy = p
+	}
+	return nil
+}
+
+func (a accountConfig) googleCredentials() googleCredentials {
+	return googleCredentials{
+		Keyfile:   a.GoogleKeyfile,
+		TokenFile: a.GoogleToken,
+		Subject:   a.GoogleSubject,
+	}
+}
+
+// includePast returns if any of the calendars of the account include past trips.
+func (a accountConfig) includePast() bool {
+	for _, t := range a.Calendars {
+		if t.Past {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedTrips returns if any of the calendars of the account handle the
+// trips shared with it, so the trips it is not traveling on are listed too.
+func (a accountConfig) sharedTrips() bool {
+	for _, t := range a.Calendars {
+		if t.Shared != sharedNone {
+			return true
+		}
+	}
+	return false
+}
+
+// events returns the events that go to the calendar target.
+func (t calendarTarget) events(events []tripit.Event, now time.Time) []tripit.Event {
+	var out []tripit.Event
+	for _, e := range events {
+		if !*t.Buffers && e.Kind == tripit.EventKindBuffer {
+			continue
+		}
+
+		if !containsKind(t.Kinds, e.Kind) {
+			continue
+		}
+
+		if len(t.ObjectTypes) > 0 && !containsType(t.ObjectTypes, e.Kind.ObjectType()) {
+			continue
+		}
+
+		if !t.Past {
+			end, err := eventEnd(e.End)
+			if err == nil && end.Before(now) {
+				continue
+			}
+		}
+
+		switch t.Shared {
+		case sharedSkip:
+			if e.SharedBy != "" {
+				continue
+			}
+		case sharedPrefix:
+			if e.SharedBy != "" {
+				e.Title = fmt.Sprintf("(shared: %s) %s", e.SharedBy, e.Title)
+			}
+		case sharedOnly:
+			if e.SharedBy == "" {
+				continue
+			}
+		}
+
+		e.ColorID = t.eventColor(e)
+
+		// The notes of the reminders are for the user, they are left out
+		// of the redacted events like the rest of the description.
+		notes := t.reminderNotes(e)
+		e = t.privacy.apply(e)
+		if notes != "" && !t.privacy.Redacted {
+			e.Description = notes + "\n\n" + e.Description
+		}
+
+		out = append(out, e)
+	}
+	return out
+}
+
+// eventObjectTypes returns the TripIt object types events are created for.
+func eventObjectTypes() []tripit.Type {
+	var types []tripit.Type
+	for _, kind := range tripit.EventKinds {
+		if !containsType(types, kind.ObjectType()) {
+			types = append(types, kind.ObjectType())
+		}
+	}
+	return types
+}
+
+func isEventObjectType(typ tripit.Type) bool {
+	return containsType(eventObjectTypes(), typ)
+}
+
+func isEventKind(kind tripit.EventKind) bool {
+	return containsKind(tripit.EventKinds, kind)
+}
+
+// defaultKinds are the kinds of events synced to a calendar that does not
+// list them, the banners of the trips and the other objects have to be
+// asked for.
+func defaultKinds() []tripit.EventKind {
+	return []tripit.EventKind{tripit.EventKindFlight, tripit.EventKindBuffer}
+}
+
+func containsKind(kinds []tripit.EventKind, kind tripit.EventKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// eventEnd returns the end of the event, the end of an all day event is
+// the start of its end date in UTC.
+func eventEnd(end calendar.EventDateTime) (time.Time, error) {
+	if end.DateTime == "" && end.Date != "" {
+		return time.Parse("2006-01-02", end.Date)
+	}
+	return time.Parse(time.RFC3339, end.DateTime)
+}
+
+func joinTypes(types []tripit.Type) string {
+	names := make([]string, 0, len(types))
+	for _, typ := range types {
+		names = append(names, string(typ))
+	}
+	return strings.Join(names, ", ")
+}
+
+func containsType(types []tripit.Type, typ tripit.Type) bool {
+	for _, t := range types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tripitcalb0t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	keyfile := filepath.Join(dir, "google.json")
+	if err := ioutil.WriteFile(keyfile, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// account is the start of a valid account, the cases add their
+	// calendars.
+	account := `accounts:
+  - tripit_username: jess@example.com
+    tripit_password: secret
+    google_keyfile: ` + keyfile + `
+`
+
+	tests := []struct {
+		name   string
+		config string
+		// err is in the error, a valid config has none.
+		err string
+		// check checks the valid config.
+		check func(t *testing.T, c *config)
+	}{
+		{
+			name: "defaults",
+			config: account + `    calendars:
+      - calendar: jess@example.com
+`,
+			check: func(t *testing.T, c *config) {
+				a := c.Accounts[0]
+				if a.Name != "jess@example.com" {
+					t.Errorf("name is %q, want the tripit username", a.Name)
+				}
+				if a.Timezone != "Local" {
+					t.Errorf("time zone is %q, want Local", a.Timezone)
+				}
+				if a.Interval != time.Minute {
+					t.Errorf("interval is %s, want the default of 1m", a.Interval)
+				}
+				if a.Concurrency != defaultWriteConcurrency {
+					t.Errorf("concurrency is %d, want %d", a.Concurrency, defaultWriteConcurrency)
+				}
+				target := a.Calendars[0]
+				if target.Shared != sharedNone || target.Attendees != attendeesNone || target.Privacy != privacyFull {
+					t.Errorf("calendar is shared %q, attendees %q, privacy %q, want the defaults", target.Shared, target.Attendees, target.Privacy)
+				}
+				if fmt.Sprint(target.Kinds) != "[flight buffer]" {
+					t.Errorf("kinds are %v, want flight and buffer", target.Kinds)
+				}
+			},
+		},
+		{
+			name: "time zone",
+			config: `accounts:
+  - tripit_username: jess@example.com
+    tripit_password: secret
+    google_keyfile: ` + keyfile + `
+    timezone: America/New_York
+    calendars:
+      - calendar: jess@example.com
+`,
+			check: func(t *testing.T, c *config) {
+				if tz := c.Accounts[0].Timezone; tz != "America/New_York" {
+					t.Errorf("time zone is %q, want America/New_York", tz)
+				}
+			},
+		},
+		{
+			name: "unknown field",
+			config: account + `    calendars:
+      - calendar: jess@example.com
+        colours:
+          flight: tomato
+`,
+			err: "field colours not found",
+		},
+		{
+			name:   "no accounts",
+			config: "accounts: []\n",
+			err:    "has no accounts",
+		},
+		{
+			name: "unknown time zone",
+			config: `accounts:
+  - tripit_username: jess@example.com
+    tripit_password: secret
+    google_keyfile: ` + keyfile + `
+    timezone: Mars/Olympus_Mons
+    calendars:
+      - calendar: jess@example.com
+`,
+			err: `loading time zone "Mars/Olympus_Mons" failed`,
+		},
+		{
+			name: "unknown kind",
+			config: account + `    calendars:
+      - calendar: jess@example.com
+        kinds: [flight, cruise]
+`,
+			err: `unknown event kind "cruise" in kinds`,
+		},
+		{
+			name: "unknown kind in colors",
+			config: account + `    calendars:
+      - calendar: jess@example.com
+        colors:
+          layover: tomato
+`,
+			err: `unknown event kind "layover" in colors`,
+		},
+		{
+			name: "unknown kind in reminders",
+			config: account + `    calendars:
+      - calendar: jess@example.com
+        reminders:
+          layover:
+            - before: 1h
+`,
+			err: `unknown event kind "layover" in reminders`,
+		},
+		{
+			name: "unsupported object type",
+			config: account + `    calendars:
+      - calendar: jess@example.com
+        object_types: [air, cruise]
+`,
+			err: `unsupported object type "cruise"`,
+		},
+		{
+			name: "object types",
+			config: account + `    calendars:
+      - calendar: jess@example.com
+        object_types: [air, lodging, car, rail, activity]
+`,
+		},
+		{
+			name: "duplicate calendar",
+			config: account + `    calendars:
+      - calendar: jess@example.com
+      - calendar: jess@example.com
+        privacy: redacted
+`,
+			err: `calendar "jess@example.com" is listed more than once`,
+		},
+		{
+			name: "same calendar in two accounts",
+			config: account + `    calendars:
+      - calendar: team@example.com
+  - tripit_username: alex@example.com
+    tripit_password: secret
+    google_keyfile: ` + keyfile + `
+    calendars:
+      - calendar: team@example.com
+`,
+		},
+		{
+			name: "unknown privacy profile",
+			config: account + `    calendars:
+      - calendar: jess@example.com
+        privacy: family
+`,
+			err: `unknown privacy profile "family"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, "config.yml")
+			if err := ioutil.WriteFile(file, []byte(tt.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			c, err := loadConfig(file, time.Minute)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error is %v, want it to contain %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.check != nil {
+				tt.check(t, c)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+)
+
+const doctorHelp = `Check the TripIt credentials, Google calendar access and clock skew.`
+
+// clockSkewURL is the URL we compare our clock with, the Google OAuth2
+// tokens are rejected if the clock is off.
+const clockSkewURL = "https://www.googleapis.com"
+
+func (cmd *doctorCommand) Name() string      { return "doctor" }
+func (cmd *doctorCommand) Args() string      { return "[OPTIONS]" }
+func (cmd *doctorCommand) ShortHelp() string { return doctorHelp }
+func (cmd *doctorCommand) LongHelp() string  { return doctorHelp }
+func (cmd *doctorCommand) Hidden() bool      { return false }
+
+func (cmd *doctorCommand) Register(fs *flag.FlagSet) {
+	registerCalendarFlags(fs)
+
+	fs.DurationVar(&cmd.maxSkew, "max-skew", 30*time.Second, "Maximum allowed difference between the local clock and the Google servers")
+}
+
+type doctorCommand struct {
+	maxSkew time.Duration
+}
+
+func (cmd *doctorCommand) Run(ctx context.Context, args []string) error {
+	checks := []struct {
+		name  string
+		check func(context.Context) (string, error)
+	}{
+		{"tripit credentials", checkTripIt},
+		{"google calendar access", checkCalendar},
+		{"clock skew", cmd.checkClockSkew},
+	}
+
+	var failed int
+	for _, c := range checks {
+		detail, err := c.check(ctx)
+		if err != nil {
+			failed++
+			fmt.Printf("[FAIL] %s: %v\n", c.name, err)
+			continue
+		}
+		fmt.Printf("[OK]   %s: %s\n", c.name, detail)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+
+	return nil
+}
+
+func checkTripIt(ctx context.Context) (string, error) {
+	if err := validateTripItFlags(); err != nil {
+		return "", err
+	}
+
+	tripitClient := tripit.New(tripitUsername, tripitPassword)
+	profile, err := tripitClient.GetCurrentProfile(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("authenticated as %s", profile.ScreenName), nil
+}
+
+func checkCalendar(ctx context.Context) (string, error) {
+	if err := validateCalendarFlags(); err != nil {
+		return "", err
+	}
+
+	gcalClient, err := newCalendarClient(ctx, flagGoogleCredentials())
+	if err != nil {
+		return "", err
+	}
+
+	cal, err := gcalClient.Calendars.Get(calendarName).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("getting google calendar %s failed: %v", calendarName, err)
+	}
+
+	return fmt.Sprintf("found calendar %q", cal.Summary), nil
+}
+
+func (cmd *doctorCommand) checkClockSkew(ctx context.Context) (string, error) {
+	req, err := http.NewRequest(http.MethodHead, clockSkewURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	before := time.Now()
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("request to %s failed: %v", clockSkewURL, err)
+	}
+	resp.Body.Close()
+	after := time.Now()
+
+	date := resp.Header.Get("Date")
+	if date == "" {
+		return "", errors.New("response has no Date header")
+	}
+	remote, err := http.ParseTime(date)
+	if err != nil {
+		return "", fmt.Errorf("parsing Date header %q failed: %v", date, err)
+	}
+
+	// Compare with the middle of the request, the Date header only has
+	// second precision anyways.
+	local := before.Add(after.Sub(before) / 2)
+	skew := local.Sub(remote).Round(time.Second)
+	if skew > cmd.maxSkew || -skew > cmd.maxSkew {
+		return "", fmt.Errorf("local clock is off by %s, more than the maximum of %s", skew, cmd.maxSkew)
+	}
+
+	return fmt.Sprintf("local clock is off by %s", skew), nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+)
+
+const exportHelp = `Export the events for the TripIt flights as ICS or CSV.`
+
+const (
+	formatICS = "ics"
+
+	icsTimeFormat = "20060102T150405Z"
+	// icsLineLength is the maximum length of a content line in octets, see RFC 5545 section 3.1.
+	icsLineLength = 75
+)
+
+func (cmd *exportCommand) Name() string      { return "export" }
+func (cmd *exportCommand) Args() string      { return "[OPTIONS]" }
+func (cmd *exportCommand) ShortHelp() string { return exportHelp }
+func (cmd *exportCommand) LongHelp() string  { return exportHelp }
+func (cmd *exportCommand) Hidden() bool      { return false }
+
+func (cmd *exportCommand) Register(fs *flag.FlagSet) {
+	cmd.eventFlags.register(fs)
+
+	fs.StringVar(&cmd.format, "format", formatICS, "Output format (ics, csv)")
+	fs.StringVar(&cmd.output, "output", "", "File to write the export to (default is stdout)")
+	fs.StringVar(&cmd.output, "o", "", "File to write the export to (default is stdout)")
+	fs.BoolVar(&cmd.shared, "shared", false, "Include the trips shared with you that you are not traveling on")
+}
+
+type exportCommand struct {
+	eventFlags
+
+	format string
+	output string
+	shared bool
+}
+
+func (cmd *exportCommand) Run(ctx context.Context, args []string) error {
+	switch cmd.format {
+	case formatICS, formatCSV:
+	default:
+		return fmt.Errorf("unknown format %q, must be one of %s, %s", cmd.format, formatICS, formatCSV)
+	}
+
+	if err := validateTripItFlags(); err != nil {
+		return err
+	}
+
+	if err := cmd.eventFlags.validate(); err != nil {
+		return err
+	}
+
+	tz, err := cmd.timezoneResolver()
+	if err != nil {
+		return err
+	}
+
+	// Create the TripIt API client.
+	tripitClient := tripit.New(tripitUsername, tripitPassword)
+
+	events, _, err := getTripItEvents(ctx, tripitClient, tz, cmd.past, cmd.shared)
+	if err != nil {
+		return err
+	}
+
+	// The export has the flights and their buffers, not the all day banners
+	// of the trips or the other objects.
+	var flights []tripit.Event
+	for _, e := range events {
+		if e.Kind == tripit.EventKindFlight || e.Kind == tripit.EventKindBuffer {
+			flights = append(flights, e)
+		}
+	}
+	events = flights
+
+	var w io.Writer = os.Stdout
+	if len(cmd.output) > 0 {
+		f, err := os.Create(cmd.output)
+		if err != nil {
+			return fmt.Errorf("creating file %s failed: %v", cmd.output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if cmd.format == formatCSV {
+		return writeEventsCSV(w, events)
+	}
+	return writeEventsICS(w, events, time.Now())
+}
+
+func writeEventsCSV(w io.Writer, events []tripit.Event) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"kind", "title", "start", "start_time_zone", "end", "end_time_zone", "location", "confirmation_number", "shared_by", "trip_id", "segment_id"})
+	for _, e := range events {
+		cw.Write([]string{
+			string(e.Kind),
+			e.Title,
+			e.Start.DateTime,
+			e.Start.TimeZone,
+			e.End.DateTime,
+			e.End.TimeZone,
+			getAirportName(e.AirportCode),
+			e.ConfirmationNumber,
+			e.SharedBy,
+			e.ID,
+			e.SegmentID,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeEventsICS(w io.Writer, events []tripit.Event, now time.Time) error {
+	bw := bufio.NewWriter(w)
+
+	writeICSLine(bw, "BEGIN", "VCALENDAR")
+	writeICSLine(bw, "VERSION", "2.0")
+	writeICSLine(bw, "PRODID", "-//tripitcalb0t//EN")
+	writeICSLine(bw, "CALSCALE", "GREGORIAN")
+
+	for _, e := range events {
+		start, err := time.Parse(time.RFC3339, e.Start.DateTime)
+		if err != nil {
+			return fmt.Errorf("parsing start time %q for segment %s failed: %v", e.Start.DateTime, e.SegmentID, err)
+		}
+		end, err := time.Parse(time.RFC3339, e.End.DateTime)
+		if err != nil {
+			return fmt.Errorf("parsing end time %q for segment %s failed: %v", e.End.DateTime, e.SegmentID, err)
+		}
+
+		writeICSLine(bw, "BEGIN", "VEVENT")
+		// The segment has a flight event and buffer events, so the kind
+		// makes the UID unique.
+		writeICSLine(bw, "UID", fmt.Sprintf("%s-%s-%s@tripitcalb0t", e.ID, e.SegmentID, e.Kind))
+		writeICSLine(bw, "DTSTAMP", now.UTC().Format(icsTimeFormat))
+		writeICSLine(bw, "DTSTART", start.UTC().Format(icsTimeFormat))
+		writeICSLine(bw, "DTEND", end.UTC().Format(icsTimeFormat))
+		writeICSLine(bw, "SUMMARY", escapeICSText(e.Title))
+		writeICSLine(bw, "DESCRIPTION", escapeICSText(e.Description))
+		if location := getAirportName(e.AirportCode); location != "" {
+			writeICSLine(bw, "LOCATION", escapeICSText(location))
+		}
+		writeICSLine(bw, "END", "VEVENT")
+	}
+
+	writeICSLine(bw, "END", "VCALENDAR")
+
+	return bw.Flush()
+}
+
+// writeICSLine writes the content line, folding it at the maximum line length.
+func writeICSLine(w *bufio.Writer, name, value string) {
+	line := name + ":" + value
+
+	n := 0
+	for _, r := range line {
+		size := len(string(r))
+		if n+size > icsLineLength {
+			w.WriteString("\r\n ")
+			// The space we fold with counts towards the next line.
+			n = 1
+		}
+		w.WriteRune(r)
+		n += size
+	}
+	w.WriteString("\r\n")
+}
+
+// escapeICSText escapes a TEXT value, see RFC 5545 section 3.3.11.
+func escapeICSText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// defaultGoogleTokenFile is the name of the file in the credentials directory
+// the OAuth2 token from "login google" is cached in.
+const defaultGoogleTokenFile = "google-token.json"
+
+// googleCredentials holds what we need to authenticate to Google.
+//
+// The keyfile is either a service account key, or the client secret of an
+// OAuth2 installed application. For a service account the calendar has to
+// be shared with it, unless Subject is set to the user to impersonate with
+// domain-wide delegation. For an installed application the user has to
+// consent with "login google" first, which caches the token in TokenFile.
+type googleCredentials struct {
+	Keyfile   string
+	TokenFile string
+	Subject   string
+}
+
+// flagGoogleCredentials returns the Google credentials from the flags.
+func flagGoogleCredentials() googleCredentials {
+	return googleCredentials{
+		Keyfile:   googleCalendarKeyfile,
+		TokenFile: googleTokenFile,
+		Subject:   googleSubject,
+	}
+}
+
+// isServiceAccount returns if the keyfile is a service account key.
+func isServiceAccount(data []byte) bool {
+	var key struct {
+		Type string `json:"type"`
+	}
+	return json.Unmarshal(data, &key) == nil && key.Type == "service_account"
+}
+
+// newCalendarClient creates a Google calendar API client from the credentials.
+func newCalendarClient(ctx context.Context, creds googleCredentials) (*calendar.Service, error) {
+	gcalData, err := ioutil.ReadFile(creds.Keyfile)
+	if err != nil {
+		return nil, fmt.Errorf("reading file %s failed: %v", creds.Keyfile, err)
+	}
+
+	var ts oauth2.TokenSource
+	if isServiceAccount(gcalData) {
+		jwtConfig, err := google.JWTConfigFromJSON(gcalData, calendar.CalendarScope)
+		if err != nil {
+			return nil, fmt.Errorf("creating google calendar token source from file %s failed: %v", creds.Keyfile, err)
+		}
+		jwtConfig.Subject = creds.Subject
+		ts = jwtConfig.TokenSource(ctx)
+	} else {
+		if creds.Subject != "" {
+			return nil, fmt.Errorf("impersonating %s requires a service account keyfile, %s is not one", creds.Subject, creds.Keyfile)
+		}
+
+		config, err := google.ConfigFromJSON(gcalData, calendar.CalendarScope)
+		if err != nil {
+			return nil, fmt.Errorf("creating google calendar oauth2 config from file %s failed: %v", creds.Keyfile, err)
+		}
+
+		tok, err := readToken(creds.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("%v, run the login google command first", err)
+		}
+
+		ts = &cachedTokenSource{
+			file: creds.TokenFile,
+			src:  oauth2.ReuseTokenSource(tok, config.TokenSource(ctx, tok)),
+			last: tok,
+		}
+	}
+
+	// Create the Google calendar client.
+	gcalClient, err := calendar.New(oauth2.NewClient(ctx, ts))
+	if err != nil {
+		return nil, fmt.Errorf("creating google calendar client failed: %v", err)
+	}
+
+	return gcalClient, nil
+}
+
+// cachedTokenSource writes the token to the file whenever it is refreshed, so
+// the next run does not have to refresh it again.
+type cachedTokenSource struct {
+	file string
+	src  oauth2.TokenSource
+
+	mu   sync.Mutex
+	last *oauth2.Token
+}
+
+// Token returns the token from the source and caches it if it changed.
+func (s *cachedTokenSource) Token() (*oauth2.Token, error) {
+	tok, err := s.src.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.last == nil || s.last.AccessToken != tok.AccessToken {
+		if err := writeToken(s.file, tok); err != nil {
+			// We can keep going with the token we have.
+			logrus.Warnf("caching refreshed google token failed: %v", err)
+		}
+		s.last = tok
+	}
+
+	return tok, nil
+}
+
+func readToken(file string) (*oauth2.Token, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading google token file %s failed: %v", file, err)
+	}
+
+	var tok oauth2.Token
+	if err := json.Unmarshal(b, &tok); err != nil {
+		return nil, fmt.Errorf("parsing google token file %s failed: %v", file, err)
+	}
+
+	return &tok, nil
+}
+
+func writeToken(file string, tok *oauth2.Token) error {
+	b, err := json.Marshal(tok)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("creating directory for google token file %s failed: %v", file, err)
+	}
+
+	// Write to a temporary file first so we never leave a partial token behind.
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return fmt.Errorf("writing google token file %s failed: %v", tmp, err)
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return fmt.Errorf("renaming google token file %s to %s failed: %v", tmp, file, err)
+	}
+
+	return nil
+}
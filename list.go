@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+)
+
+const listHelp = `List the upcoming flight segments from TripIt.`
+
+func (cmd *listCommand) Name() string      { return "list" }
+func (cmd *listCommand) Args() string      { return "[OPTIONS]" }
+func (cmd *listCommand) ShortHelp() string { return listHelp }
+func (cmd *listCommand) LongHelp() string  { return listHelp }
+func (cmd *listCommand) Hidden() bool      { return false }
+
+func (cmd *listCommand) Register(fs *flag.FlagSet) {
+	cmd.eventFlags.register(fs)
+
+	fs.StringVar(&cmd.format, "format", formatTable, "Output format (table, json)")
+	fs.BoolVar(&cmd.shared, "shared", false, "Include the trips shared with you that you are not traveling on")
+}
+
+type listCommand struct {
+	eventFlags
+
+	format string
+	shared bool
+}
+
+// segment holds the information we output for a flight segment.
+type segment struct {
+	Title              string    `json:"title"`
+	From               string    `json:"from"`
+	Start              time.Time `json:"start"`
+	End                time.Time `json:"end"`
+	TimeZone           string    `json:"time_zone,omitempty"`
+	ConfirmationNumber string    `json:"confirmation_number,omitempty"`
+	SharedBy           string    `json:"shared_by,omitempty"`
+	TripID             string    `json:"trip_id"`
+	SegmentID          string    `json:"segment_id"`
+}
+
+func (cmd *listCommand) Run(ctx context.Context, args []string) error {
+	switch cmd.format {
+	case formatTable, formatJSON:
+	default:
+		return fmt.Errorf("unknown format %q, must be one of %s, %s", cmd.format, formatTable, formatJSON)
+	}
+
+	if err := validateTripItFlags(); err != nil {
+		return err
+	}
+
+	if err := cmd.eventFlags.validate(); err != nil {
+		return err
+	}
+
+	tz, err := cmd.timezoneResolver()
+	if err != nil {
+		return err
+	}
+
+	// Create the TripIt API client.
+	tripitClient := tripit.New(tripitUsername, tripitPassword)
+
+	events, _, err := getTripItEvents(ctx, tripitClient, tz, cmd.past, cmd.shared)
+	if err != nil {
+		return err
+	}
+
+	segments, err := flightSegments(events, cmd.past, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if cmd.format == formatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(segments)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 20, 1, 3, ' ', 0)
+	fmt.Fprintln(w, "START\tFLIGHT\tFROM\tCONFIRMATION\tSHARED BY")
+	for _, s := range segments {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Start.Format("2006-01-02 15:04 MST"), s.Title, s.From, s.ConfirmationNumber, s.SharedBy)
+	}
+	return w.Flush()
+}
+
+// flightSegments returns the flight segments from the events sorted by their
+// start time. The segments that already ended are left out unless past is true.
+func flightSegments(events []tripit.Event, past bool, now time.Time) ([]segment, error) {
+	var segments []segment
+	for _, e := range events {
+		if e.Kind != tripit.EventKindFlight {
+			continue
+		}
+
+		start, err := time.Parse(time.RFC3339, e.Start.DateTime)
+		if err != nil {
+			return nil, fmt.Errorf("parsing start time %q for segment %s failed: %v", e.Start.DateTime, e.SegmentID, err)
+		}
+		end, err := time.Parse(time.RFC3339, e.End.DateTime)
+		if err != nil {
+			return nil, fmt.Errorf("parsing end time %q for segment %s failed: %v", e.End.DateTime, e.SegmentID, err)
+		}
+
+		if !past && end.Before(now) {
+			continue
+		}
+
+		// Show the times in the time zone of the airport.
+		if loc, err := time.LoadLocation(e.Start.TimeZone); err == nil && e.Start.TimeZone != "" {
+			start = start.In(loc)
+		}
+		if loc, err := time.LoadLocation(e.End.TimeZone); err == nil && e.End.TimeZone != "" {
+			end = end.In(loc)
+		}
+
+		segments = append(segments, segment{
+			Title:              e.Title,
+			From:               e.AirportCode,
+			Start:              start,
+			End:                end,
+			TimeZone:           e.Start.TimeZone,
+			ConfirmationNumber: e.ConfirmationNumber,
+			SharedBy:           e.SharedBy,
+			TripID:             e.ID,
+			SegmentID:          e.SegmentID,
+		})
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].Start.Before(segments[j].Start)
+	})
+
+	return segments, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+const loginHelp = `Login to Google with OAuth2 and cache the token, instead of using a service account.`
+
+func (cmd *loginCommand) Name() string      { return "login" }
+func (cmd *loginCommand) Args() string      { return "google" }
+func (cmd *loginCommand) ShortHelp() string { return loginHelp }
+func (cmd *loginCommand) LongHelp() string  { return loginHelp }
+func (cmd *loginCommand) Hidden() bool      { return false }
+
+func (cmd *loginCommand) Register(fs *flag.FlagSet) {
+	registerGoogleFlags(fs)
+}
+
+type loginCommand struct{}
+
+func (cmd *loginCommand) Run(ctx context.Context, args []string) error {
+	if len(args) != 1 || args[0] != "google" {
+		return errors.New("usage: tripitcalb0t login google")
+	}
+
+	gcalData, err := ioutil.ReadFile(googleCalendarKeyfile)
+	if err != nil {
+		return fmt.Errorf("reading file %s failed: %v", googleCalendarKeyfile, err)
+	}
+
+	if isServiceAccount(gcalData) {
+		return fmt.Errorf("%s is a service account keyfile, those do not need a login", googleCalendarKeyfile)
+	}
+
+	config, err := google.ConfigFromJSON(gcalData, calendar.CalendarScope)
+	if err != nil {
+		return fmt.Errorf("creating google calendar oauth2 config from file %s failed: %v", googleCalendarKeyfile, err)
+	}
+
+	// Listen on a loopback port for the redirect with the authorization code.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("listening for the oauth2 redirect failed: %v", err)
+	}
+	config.RedirectURL = fmt.Sprintf("http://%s/", l.Addr())
+
+	state, err := randomState()
+	if err != nil {
+		return err
+	}
+
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("state") != state {
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		}
+
+		if e := q.Get("error"); e != "" {
+			http.Error(w, "login failed: "+e, http.StatusBadRequest)
+			select {
+			case errs <- fmt.Errorf("login failed: %s", e):
+			default:
+			}
+			return
+		}
+
+		fmt.Fprintln(w, "Logged in, you can close this window and go back to tripitcalb0t.")
+		select {
+		case codes <- q.Get("code"):
+		default:
+		}
+	})}
+	go srv.Serve(l)
+	defer srv.Close()
+
+	fmt.Printf("Open this URL in your browser to login to Google:\n\n%s\n\n", config.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.ApprovalForce))
+
+	var code string
+	select {
+	case code = <-codes:
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	tok, err := config.Exchange(ctx, code)
+	if err != nil {
+		return fmt.Errorf("exchanging the oauth2 authorization code failed: %v", err)
+	}
+
+	if err := writeToken(googleTokenFile, tok); err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved the Google token to %s\n", googleTokenFile)
+	return nil
+}
+
+// randomState returns a random value to protect the redirect from forgery.
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating oauth2 state failed: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -5,14 +5,10 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"os"
-	"os/signal"
 	"os/user"
 	"path/filepath"
-	"strconv"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/genuinetools/pkg/cli"
@@ -20,21 +16,18 @@ import (
 	"github.com/jessfraz/tripitcalb0t/version"
 	"github.com/mmcloughlin/openflights"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/oauth2/google"
-	calendar "google.golang.org/api/calendar/v3"
 )
 
 var (
 	googleCalendarKeyfile string
+	googleTokenFile       string
+	googleSubject         string
 	calendarName          string
 	credsDir              string
 
 	tripitUsername string
 	tripitPassword string
-
-	interval time.Duration
-	once     bool
-	past     bool
+	tripitSecrets  secretSources
 
 	debug bool
 )
@@ -56,17 +49,23 @@ func main() {
 	p.GitCommit = version.GITCOMMIT
 	p.Version = version.VERSION
 
+	// Setup the commands.
+	p.Commands = []cli.Command{
+		&syncCommand{},
+		&listCommand{},
+		&exportCommand{},
+		&doctorCommand{},
+		&pointsCommand{},
+		&loginCommand{},
+	}
+
 	// Setup the global flags.
 	p.FlagSet = flag.NewFlagSet("global", flag.ExitOnError)
-	p.FlagSet.StringVar(&googleCalendarKeyfile, "google-keyfile", filepath.Join(credsDir, "google.json"), "Path to Google Calendar keyfile")
-	p.FlagSet.StringVar(&calendarName, "calendar", os.Getenv("GOOGLE_CALENDAR_ID"), "Calendar name to add events to (or env var GOOGLE_CALENDAR_ID)")
-
 	p.FlagSet.StringVar(&tripitUsername, "tripit-username", os.Getenv("TRIPIT_USERNAME"), "TripIt Username for authentication (or env var TRIPIT_USERNAME)")
 	p.FlagSet.StringVar(&tripitPassword, "tripit-password", os.Getenv("TRIPIT_PASSWORD"), "TripIt Password for authentication (or env var TRIPIT_PASSWORD)")
-
-	p.FlagSet.DurationVar(&interval, "interval", time.Minute, "Update interval (ex. 5ms, 10s, 1m, 3h)")
-	p.FlagSet.BoolVar(&once, "once", false, "Run once and exit, do not run as a daemon")
-	p.FlagSet.BoolVar(&past, "past", false, "Include past trips")
+	p.FlagSet.StringVar(&tripitSecrets.File, "tripit-password-file", os.Getenv("TRIPIT_PASSWORD_FILE"), "Path to a file with the TripIt Password (or env var TRIPIT_PASSWORD_FILE)")
+	p.FlagSet.StringVar(&tripitSecrets.Command, "tripit-password-command", os.Getenv("TRIPIT_PASSWORD_COMMAND"), "Command that prints the TripIt Password, ex. \"pass show tripit\" (or env var TRIPIT_PASSWORD_COMMAND)")
+	p.FlagSet.StringVar(&tripitSecrets.Keyring, "tripit-password-keyring", os.Getenv("TRIPIT_PASSWORD_KEYRING"), fmt.Sprintf("Keyring to get the TripIt Password from (%s) (or env var TRIPIT_PASSWORD_KEYRING)", strings.Join(keyringNames(), ", ")))
 
 	p.FlagSet.BoolVar(&debug, "debug", false, "Enable debug logging")
 	p.FlagSet.BoolVar(&debug, "d", false, "Enable debug logging")
@@ -78,229 +77,290 @@ func main() {
 			logrus.SetLevel(logrus.DebugLevel)
 		}
 
-		if len(tripitUsername) < 1 {
-			return errors.New("tripit username cannot be empty")
-		}
+		return nil
+	}
 
-		if len(tripitPassword) < 1 {
-			return errors.New("tripit password cannot be empty")
-		}
+	// Run our program.
+	p.Run()
+}
 
-		if _, err := os.Stat(googleCalendarKeyfile); os.IsNotExist(err) {
-			return fmt.Errorf("google calendar keyfile %q does not exist", googleCalendarKeyfile)
-		}
+// registerGoogleFlags registers the flags for authenticating to Google.
+func registerGoogleFlags(fs *flag.FlagSet) {
+	fs.StringVar(&googleCalendarKeyfile, "google-keyfile", filepath.Join(credsDir, "google.json"), "Path to Google Calendar keyfile, either a service account key or an OAuth2 client secret")
+	fs.StringVar(&googleTokenFile, "google-token-file", filepath.Join(credsDir, defaultGoogleTokenFile), "Path to the cached OAuth2 token from the login google command")
+	fs.StringVar(&googleSubject, "google-subject", "", "User to impersonate with the service account through domain-wide delegation")
+}
 
-		if len(calendarName) < 1 {
-			return errors.New("calendar name cannot be empty")
-		}
+// registerCalendarFlags registers the flags for the commands that use Google calendar.
+func registerCalendarFlags(fs *flag.FlagSet) {
+	registerGoogleFlags(fs)
+	fs.StringVar(&calendarName, "calendar", os.Getenv("GOOGLE_CALENDAR_ID"), "Calendar name to add events to (or env var GOOGLE_CALENDAR_ID)")
+}
 
-		return nil
+// validateTripItFlags validates the flags needed to talk to the TripIt API.
+// If a password file, command or keyring is set the password is read from it.
+func validateTripItFlags() error {
+	if len(tripitUsername) < 1 {
+		return errors.New("tripit username cannot be empty")
 	}
 
-	// Set the main program action.
-	p.Action = func(ctx context.Context, args []string) error {
-		ticker := time.NewTicker(interval)
-
-		// On ^C, or SIGTERM handle exit.
-		c := make(chan os.Signal, 1)
-		signal.Notify(c, os.Interrupt)
-		signal.Notify(c, syscall.SIGTERM)
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithCancel(ctx)
-		go func() {
-			for sig := range c {
-				cancel()
-				ticker.Stop()
-				logrus.Infof("Received %s, exiting.", sig.String())
-				os.Exit(0)
-			}
-		}()
-
-		// Create the TripIt API client.
-		tripitClient := tripit.New(tripitUsername, tripitPassword)
-
-		// Create the Google calendar API client.
-		gcalData, err := ioutil.ReadFile(googleCalendarKeyfile)
-		if err != nil {
-			logrus.Fatalf("reading file %s failed: %v", googleCalendarKeyfile, err)
-		}
-		gcalTokenSource, err := google.JWTConfigFromJSON(gcalData, calendar.CalendarScope)
+	if !tripitSecrets.isZero() {
+		password, err := tripitSecrets.resolve(tripitUsername)
 		if err != nil {
-			logrus.Fatalf("creating google calendar token source from file %s failed: %v", googleCalendarKeyfile, err)
+			return err
 		}
+		tripitPassword = password
+	}
 
-		// Create the Google calendar client.
-		gcalClient, err := calendar.New(gcalTokenSource.Client(ctx))
-		if err != nil {
-			logrus.Fatalf("creating google calendar client failed: %v", err)
-		}
+	if len(tripitPassword) < 1 {
+		return errors.New("tripit password cannot be empty")
+	}
 
-		pastFilter := fmt.Sprintf("%v", past)
+	return nil
+}
 
-		// If the user passed the once flag, just do the run once and exit.
+// validateCalendarFlags validates the flags needed to talk to Google calendar.
+func validateCalendarFlags() error {
+	if _, err := os.Stat(googleCalendarKeyfile); os.IsNotExist(err) {
+		return fmt.Errorf("google calendar keyfile %q does not exist", googleCalendarKeyfile)
+	}
 
-		run(tripitClient, gcalClient, calendarName, pastFilter)
-		logrus.Infof("Updated TripIt calendar entries in Google calendar %s", calendarName)
+	if len(calendarName) < 1 {
+		return errors.New("calendar name cannot be empty")
+	}
 
-		if !once {
-			logrus.Infof("Starting bot to update TripIt calendar entries in Google calendar %s every %s", calendarName, interval)
-			for range ticker.C {
-				run(tripitClient, gcalClient, calendarName, pastFilter)
-			}
-		}
+	return nil
+}
 
-		return nil
-	}
+// eventFlags holds the flags for the commands that turn TripIt flights into events.
+type eventFlags struct {
+	past         bool
+	homeTimezone string
+	airportsFile string
+}
 
-	// Run our program.
-	p.Run()
+func (f *eventFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.past, "past", false, "Include past trips")
+	fs.StringVar(&f.homeTimezone, "timezone", "Local", "Home time zone to use when TripIt and the airport data have none (ex. America/Los_Angeles)")
+	fs.StringVar(&f.airportsFile, "airports-file", "", "Path to an openflights airports.dat file to resolve airport time zones from")
 }
 
-func run(tripitClient *tripit.Client, gcalClient *calendar.Service, calendarName string, pastFilter string) {
-	// Get a list of events from Google calendar.
-	t := time.Now().AddDate(-4, 0, 0).Format(time.RFC3339)
-	events, err := gcalClient.Events.List(calendarName).ShowDeleted(false).SingleEvents(true).TimeMin(t).OrderBy("updated").Q("Flight").MaxResults(2500).Do()
-	if err != nil {
-		logrus.Fatalf("getting events from google calendar %s failed: %v", calendarName, err)
+func (f *eventFlags) validate() error {
+	if _, err := time.LoadLocation(f.homeTimezone); err != nil {
+		return fmt.Errorf("loading time zone %q failed: %v", f.homeTimezone, err)
 	}
 
-	trips, err := getTripItEvents(tripitClient, 1, pastFilter)
-	if err != nil {
-		logrus.Fatalf("getting tripit events failed: %v", err)
+	if len(f.airportsFile) > 0 {
+		if _, err := os.Stat(f.airportsFile); os.IsNotExist(err) {
+			return fmt.Errorf("airports file %q does not exist", f.airportsFile)
+		}
 	}
 
-	// Iterate over the trip and see if we already have a matching calendar event.
-	// If not make one and/or update the old one.
-	for _, trip := range trips {
-		if trip.ConfirmationNumber == "" {
-			logrus.Warnf("skipping trip that has no confirmation number: %#v", trip)
-			continue
+	return nil
+}
+
+// timezoneResolver creates the time zone resolver for the flags.
+func (f *eventFlags) timezoneResolver() (*tripit.TimezoneResolver, error) {
+	return newTimezoneResolver(f.homeTimezone, f.airportsFile)
+}
+
+// getTripItEvents returns the events of the trips and their objects in
+// TripIt, and the keys of the events of the ones that could not be turned
+// into events, so the sync does not prune them. With shared the trips
+// shared with the user are listed too, and the events of the trips and
+// objects the user is not traveling on have who is traveling on them in
+// SharedBy.
+func getTripItEvents(ctx context.Context, tripitClient *tripit.Client, tz *tripit.TimezoneResolver, includePast, shared bool) ([]tripit.Event, []string, error) {
+	filters := []tripit.Filter{tripit.IncludeObjects(true)}
+
+	var profile tripit.Profile
+	if shared {
+		// Get the profile of the user so we know which trips they are traveling on.
+		var err error
+		profile, err = tripitClient.GetCurrentProfile(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting profile from TripIt failed: %v", err)
 		}
 
-		var matchingEvent *calendar.Event
-		for _, e := range events.Items {
-			// We only care about TripIt events that match our tripID or segmentID.
-			if trip.Title == e.Summary &&
-				strings.Contains(e.Description, trip.SegmentID) {
-				matchingEvent = e
-				break
+		// Include the trips shared with us.
+		filters = append(filters, tripit.ByTraveler(tripit.TravelerAll))
+	}
+
+	// Iterate over the pages of trips.
+	it := tripitClient.TripIterator(includePast, filters...)
+
+	var (
+		events []tripit.Event
+		failed []string
+	)
+	err := it.ForEachPage(ctx, func(resp *tripit.Response) error {
+		// The objects are on the same page as their trip, which has the
+		// profile refs of the travelers.
+		trips := map[string]tripit.Trip{}
+		travelerRefs := map[string][]string{}
+		for _, trip := range resp.Trips {
+			trips[trip.ID] = trip
+			for _, invitee := range trip.Invitees {
+				if invitee.IsTraveler && invitee.Attributes.ProfileRef != "" {
+					travelerRefs[trip.ID] = append(travelerRefs[trip.ID], invitee.Attributes.ProfileRef)
+				}
 			}
 		}
 
-		// Get airport information.
-		airport := getAirportName(trip.AirportCode)
-
-		if matchingEvent == nil {
-			// No event was found for this trip, let's create one.
-			matchingEvent = &calendar.Event{
-				Summary:     trip.Title,
-				Description: trip.Description,
-				Start:       &trip.Start,
-				End:         &trip.End,
-				Location:    airport,
-				ColorId:     trip.ColorID,
+		// Create the banner events for the trips, the calendars that want
+		// them pick them by their kind.
+		for _, trip := range resp.Trips {
+			traveler := profile.PublicDisplayName
+			if traveler == "" {
+				traveler = profile.ScreenName
+			}
+			var sharedBy string
+			if shared {
+				sharedBy = tripSharedBy(trip, resp.Flights, profile, resp.Profiles)
+			}
+			if sharedBy != "" {
+				traveler = sharedBy
 			}
 
-			// Insert the event.
-			_, err = gcalClient.Events.Insert(calendarName, matchingEvent).Do()
+			ev, err := trip.GetTripAsEvent(traveler)
 			if err != nil {
-				logrus.Errorf("inserting google calendar event failed: %v", err)
+				// Warn on error and continue iterating through the trips.
+				logrus.Warn(err)
+				failed = append(failed, trip.EventKey())
+				continue
 			}
-			continue
+			ev.SharedBy = sharedBy
+			events = append(events, ev)
 		}
 
-		// Update our matching event.
-		matchingEvent.Summary = trip.Title
-		matchingEvent.Description = trip.Description
-		matchingEvent.Start = &trip.Start
-		matchingEvent.End = &trip.End
-		matchingEvent.Location = airport
-		matchingEvent.ColorId = trip.ColorID
+		// addEvents adds the events of an object of a trip, or the keys of
+		// its events to failed if it could not be turned into events.
+		addEvents := func(evs []tripit.Event, err error, keys []string, tripID string, isClientTraveler bool, travelers tripit.Travelers) {
+			if err != nil {
+				// Warn on error and continue iterating through the objects.
+				logrus.Warn(err)
+				failed = append(failed, keys...)
+				return
+			}
 
-		// Update the event.
-		_, err = gcalClient.Events.Update(calendarName, matchingEvent.Id, matchingEvent).Do()
-		if err != nil {
-			logrus.Errorf("updating google calendar event %s failed: %v", matchingEvent.Id, err)
+			// TripIt marks the objects we are traveling on, the others in
+			// our trips are our co-travelers'.
+			var sharedBy string
+			if shared && !isClientTraveler {
+				sharedBy = travelerName(trips[tripID], travelers, profile, resp.Profiles)
+			}
+			for i := range evs {
+				evs[i].SharedBy = sharedBy
+				evs[i].TravelerRefs = travelerRefs[tripID]
+			}
+
+			// Add to our events array.
+			events = append(events, evs...)
 		}
-	}
-}
 
-func getTripItEvents(tripitClient *tripit.Client, page int, pastFilter string) ([]tripit.Event, error) {
-	// Get a list of trips.
-	resp, err := tripitClient.ListTrips(
-		tripit.Filter{
-			Type:  tripit.FilterPast,
-			Value: pastFilter,
-		},
-		tripit.Filter{
-			Type:  tripit.FilterIncludeObjects,
-			Value: "true",
-		},
-		tripit.Filter{
-			Type:  tripit.FilterPageNum,
-			Value: fmt.Sprintf("%d", page),
-		},
-		tripit.Filter{
-			Type:  tripit.FilterPageSize,
-			Value: "25",
-		})
+		// Iterate over our objects and create/update calendar entries in Google calendar.
+		for _, flight := range resp.Flights {
+			evs, err := flight.GetFlightSegmentsAsEvents(tz)
+			addEvents(evs, err, flight.EventKeys(), flight.TripID, flight.IsClientTraveler, flight.Travelers)
+		}
+		for _, lodging := range resp.Lodging {
+			ev, err := lodging.GetLodgingAsEvent(tz)
+			addEvents([]tripit.Event{ev}, err, []string{lodging.EventKey()}, lodging.TripID, lodging.IsClientTraveler, lodging.Guests)
+		}
+		for _, car := range resp.Cars {
+			ev, err := car.GetCarAsEvent(tz)
+			addEvents([]tripit.Event{ev}, err, []string{car.EventKey()}, car.TripID, car.IsClientTraveler, car.Drivers)
+		}
+		for _, rail := range resp.Rails {
+			evs, err := rail.GetRailSegmentsAsEvents(tz)
+			addEvents(evs, err, rail.EventKeys(), rail.TripID, rail.IsClientTraveler, rail.Travelers)
+		}
+		for _, activity := range resp.Activities {
+			ev, err := activity.GetActivityAsEvent(tz)
+			addEvents([]tripit.Event{ev}, err, []string{activity.EventKey()}, activity.TripID, activity.IsClientTraveler, activity.Participants)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("listing trips from TripIt failed: %v", err)
+		return nil, nil, fmt.Errorf("listing trips from TripIt failed: %v", err)
 	}
 
-	var events []tripit.Event
+	return events, failed, nil
+}
 
-	// Iterate over our flights and create/update calendar entries in Google calendar.
-	for _, flight := range resp.Flights {
-		// Create the events for the flight.
-		evs, err := flight.GetFlightSegmentsAsEvents()
-		if err != nil {
-			// Warn on error and continue iterating through the flights.
-			logrus.Warn(err)
+// tripSharedBy returns the name of who is traveling on the trip if it is
+// shared with the user rather than theirs. The user is traveling on their
+// own trips and the ones they are invited to as a traveler, unless the trip
+// has flights and none of them are the user's.
+func tripSharedBy(trip tripit.Trip, flights []tripit.Flight, profile tripit.Profile, profiles tripit.Profiles) string {
+	var first *tripit.Flight
+	for i, flight := range flights {
+		if flight.TripID != trip.ID {
 			continue
 		}
-
-		// Add to our events array.
-		events = append(events, evs...)
-	}
-
-	// Paginate.
-	var pageNum, maxPage int
-	if resp.PageNum != "" {
-		pageNum, err = strconv.Atoi(resp.PageNum)
-		if err != nil {
-			return nil, err
+		if flight.IsClientTraveler {
+			return ""
+		}
+		if first == nil {
+			first = &flights[i]
 		}
 	}
-	if resp.MaxPage != "" {
-		maxPage, err = strconv.Atoi(resp.MaxPage)
-		if err != nil {
-			return nil, err
+	if first == nil {
+		if trip.IsTraveling(profile) {
+			return ""
 		}
+		return travelerName(trip, nil, profile, profiles)
 	}
 
-	if pageNum < maxPage {
-		pageNum++
+	return travelerName(trip, first.Travelers, profile, profiles)
+}
 
-		evs, err := getTripItEvents(tripitClient, pageNum, pastFilter)
-		if err != nil {
-			return nil, err
+// travelerName returns the name to show for who is traveling on a trip or
+// object shared with the user: the first of the travelers of the object, or
+// the first other invitee traveling on the trip.
+func travelerName(trip tripit.Trip, travelers tripit.Travelers, profile tripit.Profile, profiles tripit.Profiles) string {
+	for _, traveler := range travelers {
+		if traveler.FirstName != "" {
+			return traveler.FirstName
 		}
+	}
+	if name := trip.TravelerName(profile, profiles); name != "" {
+		return name
+	}
+	return "unknown traveler"
+}
 
-		return append(events, evs...), nil
+func newTimezoneResolver(home, airports string) (*tripit.TimezoneResolver, error) {
+	loc, err := time.LoadLocation(home)
+	if err != nil {
+		return nil, fmt.Errorf("loading time zone %q failed: %v", home, err)
 	}
 
-	if pastFilter == "true" {
-		// Get future events as well.
-		evs, err := getTripItEvents(tripitClient, 1, "false")
-		if err != nil {
-			return nil, err
-		}
+	tz := tripit.NewTimezoneResolver(loc)
+	if len(airports) < 1 {
+		return tz, nil
+	}
 
-		return append(events, evs...), nil
+	f, err := os.Open(airports)
+	if err != nil {
+		return nil, fmt.Errorf("opening airports file %s failed: %v", airports, err)
 	}
+	defer f.Close()
 
-	return events, nil
+	if err := tz.LoadAirports(f); err != nil {
+		return nil, fmt.Errorf("loading airports file %s failed: %v", airports, err)
+	}
+
+	return tz, nil
+}
+
+// eventLocation returns the location of the event, the name of its airport
+// or its address.
+func eventLocation(e tripit.Event) string {
+	if airport := getAirportName(e.AirportCode); airport != "" {
+		return airport
+	}
+	return e.Location
 }
 
 func getAirportName(code string) string {
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+	"github.com/jessfraz/tripitcalb0t/tripit/tripittest"
+)
+
+func TestGetTripItEventsShared(t *testing.T) {
+	ts := tripittest.NewServer()
+	defer ts.Close()
+
+	ts.SetProfile(tripit.Profile{
+		Attributes:        tripit.ProfileAttributes{Ref: "PROFILEREF"},
+		ScreenName:        "jess",
+		PublicDisplayName: "Jess Frazelle",
+		IsClient:          true,
+	})
+	ts.AddProfile(tripit.Profile{
+		Attributes:        tripit.ProfileAttributes{Ref: "ALEXREF"},
+		ScreenName:        "alex",
+		PublicDisplayName: "Alex Traveler",
+	})
+	jess := tripit.Traveler{FirstName: "Jess", LastName: "Frazelle"}
+	alex := tripit.Traveler{FirstName: "Alex", LastName: "Traveler"}
+	invitee := func(ref string, traveler bool) tripit.Invitee {
+		return tripit.Invitee{IsTraveler: traveler, Attributes: tripit.InviteeAttributes{ProfileRef: ref}}
+	}
+
+	// Jess flies out with Alex, but only Alex flies on to Boston.
+	ny := ts.AddTrip(tripit.Trip{DisplayName: "New York", StartDate: "2030-03-01", EndDate: "2030-03-05"})
+	ts.AddFlight(tripit.Flight{
+		TripID:           ny.ID,
+		IsClientTraveler: true,
+		Segments:         tripit.FlightSegments{testSegment("SFO", "JFK", "100", "2030-03-01", "08:00:00", "13:30:00")},
+		Travelers:        tripit.Travelers{jess, alex},
+	})
+	ts.AddFlight(tripit.Flight{
+		TripID:    ny.ID,
+		Segments:  tripit.FlightSegments{testSegment("JFK", "BOS", "200", "2030-03-02", "09:00:00", "10:15:00")},
+		Travelers: tripit.Travelers{alex},
+	})
+
+	// Alex shares their trip to Chicago with Jess, who is not traveling.
+	chicago := ts.AddTrip(tripit.Trip{
+		DisplayName: "Chicago",
+		StartDate:   "2030-04-01",
+		EndDate:     "2030-04-03",
+		Invitees:    tripit.Invitees{invitee("ALEXREF", true), invitee("PROFILEREF", false)},
+	})
+	ts.AddFlight(tripit.Flight{
+		TripID:   chicago.ID,
+		Segments: tripit.FlightSegments{testSegment("SFO", "ORD", "300", "2030-04-01", "07:00:00", "13:00:00")},
+	})
+
+	// Jess is invited to Alex's trip to Denver as a traveler.
+	ts.AddTrip(tripit.Trip{
+		DisplayName: "Denver",
+		StartDate:   "2030-05-01",
+		EndDate:     "2030-05-03",
+		Invitees:    tripit.Invitees{invitee("ALEXREF", true), invitee("PROFILEREF", true)},
+	})
+
+	tz, err := newTimezoneResolver("UTC", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		shared bool
+		// want maps the flights by their route and the trips by their
+		// name to who they are shared by.
+		want map[string]string
+	}{
+		{
+			name: "not shared",
+			want: map[string]string{
+				"SFO-JFK":  "",
+				"JFK-BOS":  "",
+				"New York": "",
+				"Denver":   "",
+			},
+		},
+		{
+			name:   "shared",
+			shared: true,
+			want: map[string]string{
+				"SFO-JFK":  "",
+				"JFK-BOS":  "Alex",
+				"New York": "",
+				"SFO-ORD":  "Alex Traveler",
+				"Chicago":  "Alex Traveler",
+				"Denver":   "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(ts.Requests())
+			events, _, err := getTripItEvents(context.Background(), ts.TripItClient(), tz, false, tt.shared)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := map[string]string{}
+			for _, e := range events {
+				switch e.Kind {
+				case tripit.EventKindFlight:
+					got[e.StartAirportCode+"-"+e.EndAirportCode] = e.SharedBy
+				case tripit.EventKindTrip:
+					got[e.Title[strings.LastIndex(e.Title, " to ")+len(" to "):]] = e.SharedBy
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("got events for %v, want %v", got, tt.want)
+			}
+			for key, want := range tt.want {
+				if sharedBy, ok := got[key]; !ok {
+					t.Errorf("no event for %s", key)
+				} else if sharedBy != want {
+					t.Errorf("%s is shared by %q, want %q", key, sharedBy, want)
+				}
+			}
+
+			// Only the shared mode looks up the profile and lists the
+			// trips of the others.
+			var profile, all bool
+			for _, r := range ts.Requests()[before:] {
+				profile = profile || r.Endpoint == "get/profile"
+				all = all || strings.Contains(r.Endpoint, "traveler/all")
+			}
+			if profile != tt.shared || all != tt.shared {
+				t.Errorf("got the profile %v and all the trips %v, want %v", profile, all, tt.shared)
+			}
+		})
+	}
+}
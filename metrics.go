@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	eventsCreated = "created"
+	eventsUpdated = "updated"
+	eventsDeleted = "deleted"
+)
+
+// syncMetrics holds the metrics of the syncs for the --listen endpoint.
+var syncMetrics = newMetrics()
+
+// metrics holds the counters and gauges we export in the Prometheus text format.
+type metrics struct {
+	mu sync.Mutex
+
+	// accounts is the number of accounts that are synced, we are ready
+	// once all of them synced successfully.
+	accounts int
+
+	syncDurationSum   metricVec
+	syncDurationCount metricVec
+	syncs             metricVec
+	lastSuccess       metricVec
+	tripitRequests    metricVec
+	events            metricVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		syncDurationSum:   metricVec{},
+		syncDurationCount: metricVec{},
+		syncs:             metricVec{},
+		lastSuccess:       metricVec{},
+		tripitRequests:    metricVec{},
+		events:            metricVec{},
+	}
+}
+
+// setAccounts sets the accounts and calendars that are synced, so their
+// metrics are exported before the first sync.
+func (m *metrics) setAccounts(accounts []accountConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.accounts = len(accounts)
+	for _, a := range accounts {
+		for _, t := range a.Calendars {
+			for _, action := range []string{eventsCreated, eventsUpdated, eventsDeleted} {
+				m.events.add(0, a.Name, t.Calendar, action)
+			}
+		}
+	}
+}
+
+// observeSync records a sync of the account.
+func (m *metrics) observeSync(account string, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.syncDurationSum.add(d.Seconds(), account)
+	m.syncDurationCount.add(1, account)
+
+	result := "success"
+	if err != nil {
+		result = "error"
+	} else {
+		m.lastSuccess.set(float64(time.Now().Unix()), account)
+	}
+	m.syncs.add(1, account, result)
+}
+
+// observeEvents records the events written to a calendar.
+func (m *metrics) observeEvents(account, calendar string, stats syncStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events.add(float64(stats.Created), account, calendar, eventsCreated)
+	m.events.add(float64(stats.Updated), account, calendar, eventsUpdated)
+	m.events.add(float64(stats.Deleted), account, calendar, eventsDeleted)
+}
+
+// observeTripItRequest records a request to the TripIt API, it is a
+// tripit.RequestObserver.
+func (m *metrics) observeTripItRequest(endpoint string, statusCode int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := strconv.Itoa(statusCode)
+	if statusCode == 0 {
+		status = "error"
+	}
+	m.tripitRequests.add(1, endpoint, status)
+}
+
+// ready returns if all the accounts synced successfully at least once.
+func (m *metrics) ready() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.accounts > 0 && len(m.lastSuccess) >= m.accounts
+}
+
+// write writes the metrics in the Prometheus text format.
+func (m *metrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	writeHeader(w, "tripitcalb0t_sync_duration_seconds", "summary", "Duration of the syncs in seconds.")
+	m.syncDurationSum.writeSamples(w, "tripitcalb0t_sync_duration_seconds_sum", "account")
+	m.syncDurationCount.writeSamples(w, "tripitcalb0t_sync_duration_seconds_count", "account")
+	m.syncs.write(w, "tripitcalb0t_syncs_total", "counter", "Number of syncs by result.", "account", "result")
+	m.lastSuccess.write(w, "tripitcalb0t_last_success_timestamp_seconds", "gauge", "Unix time of the last successful sync.", "account")
+	m.tripitRequests.write(w, "tripitcalb0t_tripit_requests_total", "counter", "Number of requests to the TripIt API by endpoint and status code.", "endpoint", "status")
+	m.events.write(w, "tripitcalb0t_events_total", "counter", "Number of Google calendar events written by action.", "account", "calendar", "action")
+}
+
+// metricVec holds the values of a metric by its label values.
+type metricVec map[string]sample
+
+type sample struct {
+	labels []string
+	value  float64
+}
+
+func (v metricVec) add(value float64, labels ...string) {
+	k := strings.Join(labels, "\x00")
+	s := v[k]
+	s.labels = labels
+	s.value += value
+	v[k] = s
+}
+
+func (v metricVec) set(value float64, labels ...string) {
+	v[strings.Join(labels, "\x00")] = sample{labels: labels, value: value}
+}
+
+func (v metricVec) write(w io.Writer, name, typ, help string, labelNames ...string) {
+	writeHeader(w, name, typ, help)
+	v.writeSamples(w, name, labelNames...)
+}
+
+func writeHeader(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+}
+
+func (v metricVec) writeSamples(w io.Writer, name string, labelNames ...string) {
+	// Sort the samples so the output is stable.
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := v[k]
+		pairs := make([]string, len(labelNames))
+		for i, label := range labelNames {
+			pairs[i] = fmt.Sprintf(`%s="%s"`, label, labelValueReplacer.Replace(s.labels[i]))
+		}
+		fmt.Fprintf(w, "%s{%s} %s\n", name, strings.Join(pairs, ","), strconv.FormatFloat(s.value, 'g', -1, 64))
+	}
+}
+
+// labelValueReplacer escapes label values for the Prometheus text format.
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// serveMetrics serves the health, readiness and metrics endpoints on addr.
+func serveMetrics(addr string, m *metrics) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !m.ready() {
+			http.Error(w, "waiting for the first successful sync", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		m.write(w)
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		logrus.Infof("Serving health and metrics on %s", addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logrus.Fatalf("serving health and metrics on %s failed: %v", addr, err)
+		}
+	}()
+
+	return srv
+}
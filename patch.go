@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// eventPatch returns the patch that makes the synced fields of the existing
+// event what we want them to be, and if anything changed. Only the fields
+// that changed are in the patch, so the fields the user owns are left alone:
+// the attendees they added are kept, the reminders, visibility and
+// transparency are only changed while they are the ones we set, and the
+// attachments and the rest are never sent.
+func eventPatch(existing, want *calendar.Event) (*calendar.Event, bool) {
+	patch := &calendar.Event{}
+	var changed []string
+
+	setString := func(field string, have, want string, dst *string) {
+		if have == want {
+			return
+		}
+		*dst = want
+		changed = append(changed, field)
+		if want == "" {
+			// An empty string is left out of the request otherwise.
+			patch.ForceSendFields = append(patch.ForceSendFields, field)
+		}
+	}
+	setString("Summary", existing.Summary, want.Summary, &patch.Summary)
+	setString("Description", existing.Description, want.Description, &patch.Description)
+	setString("Location", existing.Location, want.Location, &patch.Location)
+	setString("ColorId", existing.ColorId, want.ColorId, &patch.ColorId)
+
+	// The privacy profile sets the visibility and transparency. When it no
+	// longer does, the ones we set go back to the defaults, the ones the user
+	// changed are kept.
+	setOwned := func(field, property, have, want, reset string, dst *string) {
+		if want == "" {
+			set := eventProperty(existing, property)
+			if set == "" || have != set {
+				return
+			}
+			want = reset
+		}
+		setString(field, have, want, dst)
+	}
+	setOwned("Visibility", eventVisibilityProperty, existing.Visibility, want.Visibility, "default", &patch.Visibility)
+	setOwned("Transparency", eventTransparencyProperty, existing.Transparency, want.Transparency, "opaque", &patch.Transparency)
+
+	// The patch is sent after the caller is done with want, so it gets its
+	// own copy of the times.
+	if !sameEventTime(existing.Start, want.Start) {
+		patch.Start = copyEventTime(want.Start)
+		changed = append(changed, "Start")
+	}
+	if !sameEventTime(existing.End, want.End) {
+		patch.End = copyEventTime(want.End)
+		changed = append(changed, "End")
+	}
+
+	// The reminders are ours if they still have the hash we recorded, or
+	// they are the calendar's defaults the user did not change.
+	hash := eventProperty(existing, eventRemindersProperty)
+	ours := hash != "" && hash == remindersHash(existing.Reminders)
+	switch {
+	case want.Reminders != nil:
+		if (ours || hash == "" && usesDefaultReminders(existing.Reminders)) && !sameReminders(existing.Reminders, want.Reminders) {
+			patch.Reminders = want.Reminders
+			changed = append(changed, "Reminders")
+		}
+	case ours:
+		// The calendar no longer configures the reminders.
+		patch.Reminders = &calendar.EventReminders{
+			UseDefault:      true,
+			ForceSendFields: []string{"UseDefault", "Overrides"},
+		}
+		changed = append(changed, "Reminders")
+	}
+
+	// The attendees are replaced as a whole, so the patch has the existing
+	// ones with their responses and the ones we add.
+	attendees := &calendar.Event{Attendees: append([]*calendar.EventAttendee(nil), existing.Attendees...)}
+	var emails []string
+	for _, a := range want.Attendees {
+		emails = append(emails, a.Email)
+	}
+	addAttendees(attendees, emails)
+	if len(attendees.Attendees) != len(existing.Attendees) {
+		patch.Attendees = attendees.Attendees
+		changed = append(changed, "Attendees")
+	}
+
+	// The extended properties are merged key by key, the ones the user or
+	// other apps set are kept. The ones of the fields we no longer set are
+	// cleared.
+	if want.ExtendedProperties != nil {
+		private := map[string]string{}
+		for k, v := range want.ExtendedProperties.Private {
+			if eventProperty(existing, k) != v {
+				private[k] = v
+			}
+		}
+		for _, k := range []string{eventRemindersProperty, eventVisibilityProperty, eventTransparencyProperty} {
+			if _, ok := want.ExtendedProperties.Private[k]; !ok && eventProperty(existing, k) != "" {
+				private[k] = ""
+			}
+		}
+		if len(private) > 0 {
+			patch.ExtendedProperties = &calendar.EventExtendedProperties{Private: private}
+			changed = append(changed, "ExtendedProperties")
+		}
+	}
+
+	return patch, len(changed) > 0
+}
+
+// sameEventTime returns if the times are the same, the API can return the
+// time with the offset of the calendar rather than the one we sent.
+func sameEventTime(have, want *calendar.EventDateTime) bool {
+	if have == nil || want == nil {
+		return have == want
+	}
+	if have.Date != want.Date {
+		return false
+	}
+	if want.TimeZone != "" && have.TimeZone != want.TimeZone {
+		return false
+	}
+	if have.DateTime == want.DateTime {
+		return true
+	}
+
+	h, err := time.Parse(time.RFC3339, have.DateTime)
+	if err != nil {
+		return false
+	}
+	w, err := time.Parse(time.RFC3339, want.DateTime)
+	if err != nil {
+		return false
+	}
+	return h.Equal(w)
+}
+
+func copyEventTime(t *calendar.EventDateTime) *calendar.EventDateTime {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+// sameReminders returns if the reminders are the same, regardless of the
+// order of the overrides.
+func sameReminders(have, want *calendar.EventReminders) bool {
+	if have == nil {
+		return false
+	}
+	if have.UseDefault != want.UseDefault {
+		return false
+	}
+	return strings.Join(reminderOverrides(have), ",") == strings.Join(reminderOverrides(want), ",")
+}
+
+func reminderOverrides(r *calendar.EventReminders) []string {
+	var out []string
+	for _, o := range r.Overrides {
+		out = append(out, fmt.Sprintf("%s:%d", o.Method, o.Minutes))
+	}
+	sort.Strings(out)
+	return out
+}
+
+// usesDefaultReminders returns if the reminders are the calendar's defaults.
+func usesDefaultReminders(r *calendar.EventReminders) bool {
+	return r == nil || r.UseDefault && len(r.Overrides) == 0
+}
+
+// remindersHash returns the hash of the reminders to record on the event,
+// or an empty string for nil reminders.
+func remindersHash(r *calendar.EventReminders) string {
+	if r == nil {
+		return ""
+	}
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%t;%s", r.UseDefault, strings.Join(reminderOverrides(r), ","))
+	return fmt.Sprintf("%x", h.Sum64())
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+	"github.com/sirupsen/logrus"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+const pointsHelp = `List points programs with their balance and elite status and warn about expiring points.`
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+
+	pointsReminderColorID = "5"
+
+	// pointsKeyProperty is the private extended property with the program
+	// and the expiration date of the points of a reminder, so the reminder
+	// is found whatever its title is.
+	pointsKeyProperty = "tripitcalb0t_points"
+)
+
+func (cmd *pointsCommand) Name() string      { return "points" }
+func (cmd *pointsCommand) Args() string      { return "[OPTIONS]" }
+func (cmd *pointsCommand) ShortHelp() string { return pointsHelp }
+func (cmd *pointsCommand) LongHelp() string  { return pointsHelp }
+func (cmd *pointsCommand) Hidden() bool      { return false }
+
+func (cmd *pointsCommand) Register(fs *flag.FlagSet) {
+	registerCalendarFlags(fs)
+
+	fs.IntVar(&cmd.days, "days", 90, "Warn about points expiring within this many days")
+	fs.StringVar(&cmd.format, "format", formatTable, "Output format (table, json, csv)")
+	fs.BoolVar(&cmd.reminders, "reminders", false, "Create reminders in the Google calendar on the expiration dates")
+}
+
+type pointsCommand struct {
+	days      int
+	format    string
+	reminders bool
+}
+
+// pointsExpiration holds the points expiring for a program on a date.
+type pointsExpiration struct {
+	Program string    `json:"program"`
+	Date    time.Time `json:"date"`
+	Amount  string    `json:"amount"`
+}
+
+// pointsSummary holds the information we output for a points program.
+type pointsSummary struct {
+	Name           string             `json:"name"`
+	AccountNumber  string             `json:"account_number"`
+	Balance        string             `json:"balance"`
+	EliteStatus    string             `json:"elite_status"`
+	NextExpiration *pointsExpiration  `json:"next_expiration,omitempty"`
+	Expirations    []pointsExpiration `json:"expirations,omitempty"`
+}
+
+func (cmd *pointsCommand) Run(ctx context.Context, args []string) error {
+	switch cmd.format {
+	case formatTable, formatJSON, formatCSV:
+	default:
+		return fmt.Errorf("unknown format %q, must be one of %s, %s, %s", cmd.format, formatTable, formatJSON, formatCSV)
+	}
+
+	if cmd.days < 0 {
+		return errors.New("days cannot be negative")
+	}
+
+	if err := validateTripItFlags(); err != nil {
+		return err
+	}
+
+	if cmd.reminders {
+		if err := validateCalendarFlags(); err != nil {
+			return err
+		}
+	}
+
+	// Create the TripIt API client.
+	tripitClient := tripit.New(tripitUsername, tripitPassword)
+
+	programs, err := tripitClient.ListPointsPrograms(ctx)
+	if err != nil {
+		return fmt.Errorf("listing points programs from TripIt failed: %v", err)
+	}
+
+	summaries := summarizePointsPrograms(programs, time.Now())
+
+	// Warn about the points that are expiring soon.
+	deadline := time.Now().AddDate(0, 0, cmd.days)
+	for _, s := range summaries {
+		for _, e := range s.Expirations {
+			if e.Date.Before(deadline) {
+				logrus.Warnf("%s points expire on %s for %s", e.Amount, e.Date.Format("2006-01-02"), e.Program)
+			}
+		}
+	}
+
+	if err := writePointsSummaries(summaries, cmd.format); err != nil {
+		return err
+	}
+
+	if !cmd.reminders {
+		return nil
+	}
+
+	// Create the Google calendar API client.
+	gcalClient, err := newCalendarClient(ctx, flagGoogleCredentials())
+	if err != nil {
+		return err
+	}
+
+	return createPointsReminders(ctx, gcalClient, calendarName, summaries)
+}
+
+// summarizePointsPrograms returns the summaries of the programs with the
+// expirations after now sorted by date.
+func summarizePointsPrograms(programs []tripit.PointsProgram, now time.Time) []pointsSummary {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	summaries := make([]pointsSummary, 0, len(programs))
+	for _, program := range programs {
+		s := pointsSummary{
+			Name:          program.Name,
+			AccountNumber: program.AccountNumber,
+			Balance:       program.Balance,
+			EliteStatus:   program.EliteStatus,
+		}
+
+		for _, e := range program.Expirations {
+			date, err := time.ParseInLocation("2006-01-02", e.Date, time.Local)
+			if err != nil {
+				logrus.Warnf("parsing expiration date %q for %s failed: %v", e.Date, program.Name, err)
+				continue
+			}
+
+			// Ignore the expirations that already happened.
+			if date.Before(today) {
+				continue
+			}
+
+			s.Expirations = append(s.Expirations, pointsExpiration{
+				Program: program.Name,
+				Date:    date,
+				Amount:  e.Amount,
+			})
+		}
+
+		sort.Slice(s.Expirations, func(i, j int) bool {
+			return s.Expirations[i].Date.Before(s.Expirations[j].Date)
+		})
+		if len(s.Expirations) > 0 {
+			s.NextExpiration = &s.Expirations[0]
+		}
+
+		summaries = append(summaries, s)
+	}
+
+	return summaries
+}
+
+func writePointsSummaries(summaries []pointsSummary, format string) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summaries)
+	case formatCSV:
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"name", "account_number", "balance", "elite_status", "next_expiration", "next_expiration_amount"})
+		for _, s := range summaries {
+			date, amount := nextExpiration(s)
+			w.Write([]string{s.Name, s.AccountNumber, s.Balance, s.EliteStatus, date, amount})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 20, 1, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tACCOUNT\tBALANCE\tELITE STATUS\tNEXT EXPIRATION")
+	for _, s := range summaries {
+		date, amount := nextExpiration(s)
+		if date != "" {
+			date = fmt.Sprintf("%s (%s)", date, amount)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Name, s.AccountNumber, s.Balance, s.EliteStatus, date)
+	}
+	return w.Flush()
+}
+
+func nextExpiration(s pointsSummary) (string, string) {
+	if s.NextExpiration == nil {
+		return "", ""
+	}
+	return s.NextExpiration.Date.Format("2006-01-02"), s.NextExpiration.Amount
+}
+
+// createPointsReminders creates all day events on the expiration dates of the
+// points, and patches the ones that already exist. A reminder that fails does
+// not stop the others, the error has the ones that failed.
+func createPointsReminders(ctx context.Context, gcalClient *calendar.Service, calendarName string, summaries []pointsSummary) error {
+	// The reminders that are over are left alone.
+	var events []*calendar.Event
+	err := gcalClient.Events.List(calendarName).ShowDeleted(false).SingleEvents(true).TimeMin(time.Now().Format(time.RFC3339)).MaxResults(2500).Pages(ctx, func(page *calendar.Events) error {
+		events = append(events, page.Items...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("getting events from google calendar %s failed: %v", calendarName, err)
+	}
+
+	var errs []error
+	for _, s := range summaries {
+		for _, e := range s.Expirations {
+			summary := fmt.Sprintf("%s points expire (%s)", e.Program, e.Amount)
+			date := e.Date.Format("2006-01-02")
+			key := e.Program + "/" + date
+
+			want := &calendar.Event{
+				Summary:     summary,
+				Description: strings.TrimSpace(fmt.Sprintf("%s points from %s %s expire today.", e.Amount, e.Program, s.AccountNumber)),
+				Start:       &calendar.EventDateTime{Date: date},
+				End:         &calendar.EventDateTime{Date: e.Date.AddDate(0, 0, 1).Format("2006-01-02")},
+				ColorId:     pointsReminderColorID,
+				ExtendedProperties: &calendar.EventExtendedProperties{
+					Private: map[string]string{pointsKeyProperty: key},
+				},
+			}
+
+			existing := pointsReminder(events, key, summary, date)
+			if existing == nil {
+				if _, err := gcalClient.Events.Insert(calendarName, want).Context(ctx).Do(); err != nil {
+					logrus.Errorf("inserting google calendar event for %s failed: %v", summary, err)
+					errs = append(errs, fmt.Errorf("inserting %q on %s failed: %v", summary, date, err))
+					continue
+				}
+				logrus.Infof("Created reminder in Google calendar %s: %s on %s", calendarName, summary, date)
+				continue
+			}
+
+			patch, changed := eventPatch(existing, want)
+			if !changed {
+				continue
+			}
+			if _, err := gcalClient.Events.Patch(calendarName, existing.Id, patch).Context(ctx).Do(); err != nil {
+				logrus.Errorf("patching google calendar event for %s failed: %v", summary, err)
+				errs = append(errs, fmt.Errorf("patching %q on %s failed: %v", summary, date, err))
+				continue
+			}
+			logrus.Infof("Updated reminder in Google calendar %s: %s on %s", calendarName, summary, date)
+		}
+	}
+
+	return writesError(calendarName, errs)
+}
+
+// pointsReminder returns the reminder for the points by its key, or by its
+// title and date for the reminders from before they had keys. It returns
+// nil if there is none.
+func pointsReminder(events []*calendar.Event, key, summary, date string) *calendar.Event {
+	for _, event := range events {
+		if eventProperty(event, pointsKeyProperty) == key {
+			return event
+		}
+	}
+	for _, event := range events {
+		if eventProperty(event, pointsKeyProperty) == "" && event.Summary == summary && event.Start != nil && event.Start.Date == date {
+			return event
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/calendartest"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func testPointsSummaries() []pointsSummary {
+	return []pointsSummary{{
+		Name:          "MileagePlus",
+		AccountNumber: "MP123",
+		Expirations: []pointsExpiration{
+			{Program: "MileagePlus", Date: time.Date(2030, time.June, 1, 0, 0, 0, 0, time.UTC), Amount: "1,000"},
+			{Program: "MileagePlus", Date: time.Date(2030, time.December, 1, 0, 0, 0, 0, time.UTC), Amount: "2,000"},
+		},
+	}}
+}
+
+func TestCreatePointsReminders(t *testing.T) {
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := createPointsReminders(ctx, cs.Service(), testCalendar, testPointsSummaries()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The second run skips the reminders that exist.
+	if n := len(cs.Events(testCalendar)); n != 2 {
+		t.Errorf("calendar has %d reminders, want 2", n)
+	}
+	if n := countRequests(cs, "patch"); n != 0 {
+		t.Errorf("sent %d patches for the reminders that did not change, want 0", n)
+	}
+}
+
+func TestCreatePointsRemindersPatch(t *testing.T) {
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	ctx := context.Background()
+	if err := createPointsReminders(ctx, cs.Service(), testCalendar, testPointsSummaries()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The user renames a reminder and more points expire on its date.
+	for _, e := range cs.Events(testCalendar) {
+		if e.Start.Date == "2030-06-01" {
+			if _, err := cs.Service().Events.Patch(testCalendar, e.Id, &calendar.Event{Summary: "Use the miles!"}).Do(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	summaries := testPointsSummaries()
+	summaries[0].Expirations[0].Amount = "1,500"
+	if err := createPointsReminders(ctx, cs.Service(), testCalendar, summaries); err != nil {
+		t.Fatal(err)
+	}
+
+	events := cs.Events(testCalendar)
+	if len(events) != 2 {
+		t.Fatalf("calendar has %d reminders, want 2", len(events))
+	}
+	for _, e := range events {
+		if e.Start.Date == "2030-06-01" && e.Summary != "MileagePlus points expire (1,500)" {
+			t.Errorf("reminder is %q, want it patched with the new amount", e.Summary)
+		}
+	}
+}
+
+func TestCreatePointsRemindersPages(t *testing.T) {
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	// The reminders are after more events than fit on a page.
+	for i := 0; i < 2600; i++ {
+		cs.AddEvent(testCalendar, testEvent(fmt.Sprintf("Meeting %d", i)))
+	}
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := createPointsReminders(ctx, cs.Service(), testCalendar, testPointsSummaries()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := countRequests(cs, "insert"); n != 2 {
+		t.Errorf("sent %d inserts, want 2", n)
+	}
+}
+
+func TestCreatePointsRemindersFailed(t *testing.T) {
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	ctx := context.Background()
+	cs.Fail("insert", http.StatusForbidden, -1)
+	err := createPointsReminders(ctx, cs.Service(), testCalendar, testPointsSummaries())
+	if err == nil {
+		t.Fatal("expected an error when the inserts fail")
+	}
+	if !strings.HasPrefix(err.Error(), "2 writes to google calendar "+testCalendar+" failed") {
+		t.Errorf("error is %q, want it to have the number of reminders that failed", err)
+	}
+	if n := countRequests(cs, "insert"); n != 2 {
+		t.Errorf("sent %d inserts, want 2, one failure should not stop the others", n)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+const (
+	// privacyFull renders all the fields, it is the default.
+	privacyFull = "full"
+	// privacyRedacted only shows the route of the flights.
+	privacyRedacted = "redacted"
+
+	transparencyBusy = "busy"
+	transparencyFree = "free"
+)
+
+// privacyProfile controls what the events on a calendar show.
+type privacyProfile struct {
+	// Fields are the parts of the description that are rendered, it
+	// defaults to all of them.
+	Fields []tripit.DescriptionField `yaml:"fields"`
+	// Redacted titles the events "Traveling: SFO → JFK", or "Traveling"
+	// for the trips, without a description or location.
+	Redacted bool `yaml:"redacted"`
+	// Visibility is the visibility of the events (default, public,
+	// private, confidential), the calendar's default if it is not set.
+	Visibility string `yaml:"visibility"`
+	// Transparency is whether the events block time (busy, free), busy if
+	// it is not set.
+	Transparency string `yaml:"transparency"`
+}
+
+// builtinPrivacyProfiles are the profiles that do not have to be in the
+// config file.
+var builtinPrivacyProfiles = map[string]privacyProfile{
+	privacyFull:     {},
+	privacyRedacted: {Redacted: true},
+}
+
+func (p privacyProfile) validate() error {
+	for _, field := range p.Fields {
+		if !isDescriptionField(field) {
+			return fmt.Errorf("unknown description field %q, must be one of %s", field, joinFields(tripit.DescriptionFields))
+		}
+	}
+
+	switch p.Visibility {
+	case "", "default", "public", "private", "confidential":
+	default:
+		return fmt.Errorf("unknown visibility %q, must be one of default, public, private, confidential", p.Visibility)
+	}
+
+	switch p.Transparency {
+	case "", transparencyBusy, transparencyFree:
+	default:
+		return fmt.Errorf("unknown transparency %q, must be one of %s, %s", p.Transparency, transparencyBusy, transparencyFree)
+	}
+
+	return nil
+}
+
+// apply renders the event for the profile.
+func (p privacyProfile) apply(e tripit.Event) tripit.Event {
+	if p.Redacted {
+		e.Title = "Traveling"
+		if e.StartAirportCode != "" || e.EndAirportCode != "" {
+			e.Title = fmt.Sprintf("Traveling: %s → %s", e.StartAirportCode, e.EndAirportCode)
+		}
+		e.Description = ""
+		e.AirportCode = ""
+		e.Location = ""
+		return e
+	}
+
+	if len(p.Fields) > 0 {
+		e.Description = e.DescriptionOf(p.Fields)
+	}
+	return e
+}
+
+// transparency returns the transparency of the events in the calendar API,
+// or an empty string to leave it alone.
+func (p privacyProfile) transparency() string {
+	switch p.Transparency {
+	case transparencyBusy:
+		return "opaque"
+	case transparencyFree:
+		return "transparent"
+	}
+	return ""
+}
+
+// setEventPrivacy sets the visibility and transparency of the profile on
+// the calendar event.
+func (p privacyProfile) setEventPrivacy(event *calendar.Event) {
+	if p.Visibility != "" {
+		event.Visibility = p.Visibility
+	}
+	if t := p.transparency(); t != "" {
+		event.Transparency = t
+	}
+}
+
+func validatePrivacyProfiles(profiles map[string]privacyProfile) error {
+	for name, p := range profiles {
+		if _, ok := builtinPrivacyProfiles[name]; ok {
+			return fmt.Errorf("privacy profile %q is built in and cannot be redefined", name)
+		}
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("privacy profile %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func isDescriptionField(field tripit.DescriptionField) bool {
+	for _, f := range tripit.DescriptionFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func joinFields(fields []tripit.DescriptionField) string {
+	var s string
+	for i, f := range fields {
+		if i > 0 {
+			s += ", "
+		}
+		s += string(f)
+	}
+	return s
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+)
+
+func TestPrivacyEvents(t *testing.T) {
+	flights, err := tripit.Flight{
+		ID:              "1",
+		TripID:          "2",
+		SupplierConfNum: "ABC123",
+		Segments:        tripit.FlightSegments{testSegment("SFO", "JFK", "100", "2030-03-01", "08:00:00", "13:30:00")},
+	}.GetFlightSegmentsAsEvents(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var flight tripit.Event
+	for _, e := range flights {
+		if e.Kind == tripit.EventKindFlight {
+			flight = e
+		}
+	}
+	hotel, err := tripit.Lodging{
+		ID:            "3",
+		TripID:        "2",
+		SupplierName:  "Example Hotel",
+		StartDateTime: tripit.DateTime{Date: "2030-03-01", Time: "15:00:00", UTCOffset: "-05:00"},
+		EndDateTime:   tripit.DateTime{Date: "2030-03-05", Time: "11:00:00", UTCOffset: "-05:00"},
+		Address:       tripit.Address{Addr1: "1 Main St", City: "New York"},
+	}.GetLodgingAsEvent(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reminders := map[tripit.EventKind][]reminderRule{
+		tripit.EventKindFlight:  {{Before: 24 * time.Hour, Note: "Check in now"}},
+		tripit.EventKindLodging: {{Before: 24 * time.Hour, Note: "Check in at the hotel"}},
+	}
+
+	tests := []struct {
+		name    string
+		privacy privacyProfile
+		event   tripit.Event
+		// title and location are what the event has, and description
+		// what its description has, or that it is empty.
+		title       string
+		description string
+		location    string
+	}{
+		{
+			name:        "full flight",
+			event:       flight,
+			title:       flight.Title,
+			description: "Check in now\n\n" + flight.Description,
+			location:    "San Francisco International Airport",
+		},
+		{
+			name:        "redacted flight",
+			privacy:     builtinPrivacyProfiles[privacyRedacted],
+			event:       flight,
+			title:       "Traveling: SFO → JFK",
+			description: "",
+			location:    "",
+		},
+		{
+			name:        "full lodging",
+			event:       hotel,
+			title:       "Stay at Example Hotel",
+			description: "Check in at the hotel\n\n" + hotel.Description,
+			location:    "1 Main St, New York",
+		},
+		{
+			name:        "redacted lodging",
+			privacy:     builtinPrivacyProfiles[privacyRedacted],
+			event:       hotel,
+			title:       "Traveling",
+			description: "",
+			location:    "",
+		},
+		{
+			name:        "route only",
+			privacy:     privacyProfile{Fields: []tripit.DescriptionField{tripit.FieldRoute}},
+			event:       flight,
+			title:       flight.Title,
+			description: "Check in now\n\n" + flight.DescriptionOf([]tripit.DescriptionField{tripit.FieldRoute}),
+			location:    eventLocation(flight),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := testTarget(t, calendarTarget{
+				Calendar:  testCalendar,
+				Kinds:     []tripit.EventKind{tripit.EventKindFlight, tripit.EventKindLodging},
+				Reminders: reminders,
+			})
+			target.privacy = tt.privacy
+
+			events := target.events([]tripit.Event{tt.event}, time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC))
+			if len(events) != 1 {
+				t.Fatalf("got %d events, want 1", len(events))
+			}
+			e := events[0]
+			if e.Title != tt.title {
+				t.Errorf("title is %q, want %q", e.Title, tt.title)
+			}
+			if e.Description != tt.description {
+				t.Errorf("description is %q, want %q", e.Description, tt.description)
+			}
+			if location := eventLocation(e); location != tt.location {
+				t.Errorf("location is %q, want %q", location, tt.location)
+			}
+			if tt.privacy.Redacted {
+				for _, secret := range []string{"ABC123", "Example Hotel", "Main St", "Check in"} {
+					if strings.Contains(e.Title+e.Description+eventLocation(e), secret) {
+						t.Errorf("redacted event has %q", secret)
+					}
+				}
+			}
+		})
+	}
+}
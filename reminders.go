@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+const (
+	reminderPopup = "popup"
+	reminderEmail = "email"
+
+	// maxReminders is the most reminder overrides Google calendar allows on
+	// an event.
+	maxReminders = 5
+	// maxReminderBefore is the earliest Google calendar allows a reminder
+	// before the event.
+	maxReminderBefore = 4 * 7 * 24 * time.Hour
+
+	// checkInURLPlaceholder is replaced with the check-in URL of the flight
+	// in the notes of the reminders.
+	checkInURLPlaceholder = "{check_in_url}"
+)
+
+// reminderRule is a reminder for the events of a kind.
+type reminderRule struct {
+	// Method is how the reminder is sent (popup, email), it defaults to
+	// popup.
+	Method string `yaml:"method"`
+	// Before is how long before the start of the event the reminder is sent.
+	Before time.Duration `yaml:"before"`
+	// Note is put at the top of the description of the event, since the
+	// reminders themselves have no text.
+	Note string `yaml:"note"`
+}
+
+func (r reminderRule) validate() error {
+	switch r.Method {
+	case reminderPopup, reminderEmail:
+	default:
+		return fmt.Errorf("unknown reminder method %q, must be one of %s, %s", r.Method, reminderPopup, reminderEmail)
+	}
+
+	if r.Before < 0 || r.Before > maxReminderBefore {
+		return fmt.Errorf("reminder before %s must be between 0 and %s", r.Before, maxReminderBefore)
+	}
+	if r.Before%time.Minute != 0 {
+		return fmt.Errorf("reminder before %s must be in whole minutes", r.Before)
+	}
+
+	return nil
+}
+
+func validateReminders(reminders map[tripit.EventKind][]reminderRule) error {
+	for kind, rules := range reminders {
+		if !isEventKind(kind) {
+			return fmt.Errorf("unknown event kind %q in reminders", kind)
+		}
+		if len(rules) > maxReminders {
+			return fmt.Errorf("%s events can have at most %d reminders", kind, maxReminders)
+		}
+		for _, r := range rules {
+			if err := r.validate(); err != nil {
+				return fmt.Errorf("%s reminder: %v", kind, err)
+			}
+		}
+	}
+	return nil
+}
+
+// eventReminders returns the reminders for an event of the kind, or nil to
+// leave the reminders of the event alone. An empty list of rules turns the
+// reminders off.
+func (t calendarTarget) eventReminders(kind tripit.EventKind) *calendar.EventReminders {
+	rules, ok := t.Reminders[kind]
+	if !ok {
+		return nil
+	}
+
+	reminders := &calendar.EventReminders{
+		// UseDefault is false and there may be no overrides, so they have
+		// to be sent explicitly.
+		ForceSendFields: []string{"UseDefault", "Overrides"},
+	}
+	for _, r := range rules {
+		reminders.Overrides = append(reminders.Overrides, &calendar.EventReminder{
+			Method:          r.Method,
+			Minutes:         int64(r.Before / time.Minute),
+			ForceSendFields: []string{"Minutes"},
+		})
+	}
+	return reminders
+}
+
+// reminderNotes returns the notes of the reminders for the event, to put at
+// the top of its description.
+func (t calendarTarget) reminderNotes(e tripit.Event) string {
+	var notes []string
+	for _, r := range t.Reminders[e.Kind] {
+		if r.Note == "" {
+			continue
+		}
+		note := strings.Replace(r.Note, checkInURLPlaceholder, e.CheckInURL, -1)
+		notes = append(notes, strings.TrimSpace(note))
+	}
+	return strings.Join(notes, "\n")
+}
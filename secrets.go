@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// keyringService is the service name the secrets are stored under in the keyring.
+const keyringService = "tripitcalb0t"
+
+// keyring is a store for secrets, like the system keyring.
+type keyring interface {
+	// Get returns the secret for the user of the service.
+	Get(service, user string) (string, error)
+}
+
+// keyrings holds the keyrings by the name they are selected with.
+var keyrings = map[string]keyring{
+	// The freedesktop.org secret service, GNOME Keyring and KWallet on Linux.
+	"secret-service": commandKeyring(func(service, user string) []string {
+		return []string{"secret-tool", "lookup", "service", service, "username", user}
+	}),
+	// The macOS keychain.
+	"keychain": commandKeyring(func(service, user string) []string {
+		return []string{"security", "find-generic-password", "-s", service, "-a", user, "-w"}
+	}),
+}
+
+// commandKeyring is a keyring that gets secrets by running the command it
+// returns for the service and user.
+type commandKeyring func(service, user string) []string
+
+// Get returns the secret for the user of the service.
+func (k commandKeyring) Get(service, user string) (string, error) {
+	return runSecretCommand(k(service, user))
+}
+
+// secretSources holds the places other than a flag or environment variable
+// that a secret can be read from, so it does not end up in the output of
+// ps or the shell history. At most one of them can be set.
+type secretSources struct {
+	// File is the path to a file with the secret, like a Docker or
+	// Kubernetes secret mount.
+	File string
+	// Command is a command that prints the secret on the first line of its
+	// output, like "pass show tripit".
+	Command string
+	// Keyring is the name of the keyring to get the secret from.
+	Keyring string
+}
+
+func (s secretSources) isZero() bool {
+	return s == secretSources{}
+}
+
+// resolve returns the secret for the user from the source that is set.
+func (s secretSources) resolve(user string) (string, error) {
+	var set int
+	for _, v := range []string{s.File, s.Command, s.Keyring} {
+		if v != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return "", errors.New("only one of the password file, command or keyring can be set")
+	}
+
+	switch {
+	case s.File != "":
+		b, err := ioutil.ReadFile(s.File)
+		if err != nil {
+			return "", fmt.Errorf("reading password file %s failed: %v", s.File, err)
+		}
+		secret := strings.TrimRight(string(b), "\r\n")
+		if secret == "" {
+			return "", fmt.Errorf("password file %s is empty", s.File)
+		}
+		return secret, nil
+	case s.Command != "":
+		secret, err := runSecretCommand(strings.Fields(s.Command))
+		if err != nil {
+			return "", fmt.Errorf("password command: %v", err)
+		}
+		return secret, nil
+	case s.Keyring != "":
+		k, ok := keyrings[s.Keyring]
+		if !ok {
+			return "", fmt.Errorf("unknown keyring %q, must be one of %s", s.Keyring, strings.Join(keyringNames(), ", "))
+		}
+		secret, err := k.Get(keyringService, user)
+		if err != nil {
+			return "", fmt.Errorf("getting password for %s from keyring %s failed: %v", user, s.Keyring, err)
+		}
+		return secret, nil
+	}
+
+	return "", nil
+}
+
+// runSecretCommand runs the command and returns the first line of its output.
+func runSecretCommand(args []string) (string, error) {
+	if len(args) < 1 {
+		return "", errors.New("command cannot be empty")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("running %s failed: %v: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("running %s failed: %v", args[0], err)
+	}
+
+	secret := strings.SplitN(stdout.String(), "\n", 2)[0]
+	secret = strings.TrimRight(secret, "\r")
+	if secret == "" {
+		return "", fmt.Errorf("%s printed an empty secret", args[0])
+	}
+
+	return secret, nil
+}
+
+func keyringNames() []string {
+	names := make([]string, 0, len(keyrings))
+	for name := range keyrings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecretSourcesResolve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tripitcalb0t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	keyrings["test"] = commandKeyring(func(service, user string) []string {
+		return []string{"echo", service + "-" + user}
+	})
+	defer delete(keyrings, "test")
+
+	tests := []struct {
+		name    string
+		sources secretSources
+		want    string
+		// err is in the error, the sources that resolve have none.
+		err string
+	}{
+		{
+			name:    "file",
+			sources: secretSources{File: file("password", "secret")},
+			want:    "secret",
+		},
+		{
+			name:    "file with a trailing newline",
+			sources: secretSources{File: file("newline", "secret\n")},
+			want:    "secret",
+		},
+		{
+			name:    "file with a trailing carriage return",
+			sources: secretSources{File: file("crlf", "secret\r\n")},
+			want:    "secret",
+		},
+		{
+			name:    "empty file",
+			sources: secretSources{File: file("empty", "")},
+			err:     "is empty",
+		},
+		{
+			name:    "file with only a newline",
+			sources: secretSources{File: file("blank", "\n")},
+			err:     "is empty",
+		},
+		{
+			name:    "missing file",
+			sources: secretSources{File: filepath.Join(dir, "missing")},
+			err:     "reading password file",
+		},
+		{
+			name:    "command",
+			sources: secretSources{Command: "printf secret\\nsecond-line"},
+			want:    "secret",
+		},
+		{
+			name:    "failing command",
+			sources: secretSources{Command: "false"},
+			err:     "password command: running false failed",
+		},
+		{
+			name:    "command that prints nothing",
+			sources: secretSources{Command: "true"},
+			err:     "printed an empty secret",
+		},
+		{
+			name:    "keyring",
+			sources: secretSources{Keyring: "test"},
+			want:    keyringService + "-jess@example.com",
+		},
+		{
+			name:    "unknown keyring",
+			sources: secretSources{Keyring: "vault"},
+			err:     `unknown keyring "vault"`,
+		},
+		{
+			name:    "more than one",
+			sources: secretSources{File: file("both", "secret"), Command: "printf secret"},
+			err:     "only one of",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.sources.resolve("jess@example.com")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error is %v, want it to contain %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("secret is %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// defaultStateFile is the name of the file in the credentials directory the
+// state of the syncs is kept in.
+const defaultStateFile = "state.json"
+
+// syncState is the state of the syncs we keep between runs.
+type syncState struct {
+	mu   sync.Mutex
+	file string
+
+	Accounts map[string]accountState `json:"accounts"`
+}
+
+// accountState is the state of the syncs of an account.
+type accountState struct {
+	LastSync    time.Time  `json:"last_sync"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	// Completed is false if the last sync failed or was interrupted.
+	Completed bool   `json:"completed"`
+	Error     string `json:"error,omitempty"`
+
+	// Calendars is the state of the syncs to each of the calendars of the
+	// account, by calendar ID.
+	Calendars map[string]calendarState `json:"calendars,omitempty"`
+}
+
+// calendarState is the state of the syncs to a calendar of an account.
+type calendarState struct {
+	LastSync    time.Time  `json:"last_sync"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	// Completed is false if the last sync failed or was interrupted.
+	Completed bool   `json:"completed"`
+	Error     string `json:"error,omitempty"`
+	// Created, Updated and Deleted are the writes of the last sync.
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Deleted int `json:"deleted"`
+}
+
+// loadState reads the state from the file, a missing file is an empty state.
+func loadState(file string) (*syncState, error) {
+	s := &syncState{
+		file:     file,
+		Accounts: map[string]accountState{},
+	}
+
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading state file %s failed: %v", file, err)
+	}
+
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("parsing state file %s failed: %v", file, err)
+	}
+	if s.Accounts == nil {
+		s.Accounts = map[string]accountState{}
+	}
+
+	return s, nil
+}
+
+// record records the result of a sync of the account.
+func (s *syncState) record(account string, at time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := s.Accounts[account]
+	a.LastSync = at
+	a.Completed = err == nil
+	a.Error = ""
+	if err != nil {
+		a.Error = err.Error()
+	} else {
+		a.LastSuccess = &at
+	}
+	s.Accounts[account] = a
+}
+
+// recordCalendar records the result of a sync to a calendar of the account.
+func (s *syncState) recordCalendar(account, calendar string, at time.Time, stats syncStats, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := s.Accounts[account]
+	if a.Calendars == nil {
+		a.Calendars = map[string]calendarState{}
+	}
+	c := a.Calendars[calendar]
+	c.LastSync = at
+	c.Completed = err == nil
+	c.Error = ""
+	if err != nil {
+		c.Error = err.Error()
+	} else {
+		c.LastSuccess = &at
+	}
+	c.Created, c.Updated, c.Deleted = stats.Created, stats.Updated, stats.Deleted
+	a.Calendars[calendar] = c
+	s.Accounts[account] = a
+}
+
+// save writes the state to the file.
+func (s *syncState) save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.file), 0700); err != nil {
+		return fmt.Errorf("creating directory for state file %s failed: %v", s.file, err)
+	}
+
+	// Write to a temporary file first so we never leave a partial state behind.
+	tmp := s.file + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return fmt.Errorf("writing state file %s failed: %v", tmp, err)
+	}
+	if err := os.Rename(tmp, s.file); err != nil {
+		return fmt.Errorf("renaming state file %s to %s failed: %v", tmp, s.file, err)
+	}
+
+	return nil
+}
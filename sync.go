@@ -0,0 +1,612 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+	"github.com/sirupsen/logrus"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+const syncHelp = `Sync the TripIt flights to Google calendar events.`
+
+const (
+	// sharedNone lists the trips with the TripIt defaults, the ones the user
+	// is traveling on, without looking up who is traveling on them.
+	sharedNone = "none"
+	// sharedSkip skips the events for trips the user is not traveling on.
+	sharedSkip = "skip"
+	// sharedPrefix prefixes the title of events for trips the user is not traveling on.
+	sharedPrefix = "prefix"
+	// sharedOnly only syncs the events for trips the user is not traveling on.
+	sharedOnly = "only"
+	// sharedCalendar puts the events for trips the user is not traveling on in their own calendar.
+	// It is only valid for the --shared flag, in the config file it is a
+	// second calendar with shared set to only.
+	sharedCalendar = "calendar"
+)
+
+const (
+	// eventKeyProperty is the private extended property with the key of the
+	// TripIt event, so the calendar events are found whatever their title
+	// and description are.
+	eventKeyProperty = "tripitcalb0t_key"
+	// eventOwnerProperty is the private extended property with the TripIt
+	// username of the account that synced the event, so the accounts that
+	// sync to the same calendar only touch their own events.
+	eventOwnerProperty = "tripitcalb0t_owner"
+	// eventRemindersProperty is the private extended property with the hash
+	// of the reminders we set on the event, so the reminders the user
+	// changed are left alone.
+	eventRemindersProperty = "tripitcalb0t_reminders"
+	// eventVisibilityProperty and eventTransparencyProperty are the private
+	// extended properties with the visibility and transparency we set on
+	// the event, so they go back to the defaults when the privacy profile
+	// no longer sets them.
+	eventVisibilityProperty   = "tripitcalb0t_visibility"
+	eventTransparencyProperty = "tripitcalb0t_transparency"
+)
+
+func (cmd *syncCommand) Name() string      { return "sync" }
+func (cmd *syncCommand) Args() string      { return "[OPTIONS]" }
+func (cmd *syncCommand) ShortHelp() string { return syncHelp }
+func (cmd *syncCommand) LongHelp() string  { return syncHelp }
+func (cmd *syncCommand) Hidden() bool      { return false }
+
+func (cmd *syncCommand) Register(fs *flag.FlagSet) {
+	registerCalendarFlags(fs)
+	cmd.eventFlags.register(fs)
+
+	fs.StringVar(&cmd.configFile, "config", "", "Path to a config file with the accounts and calendars to sync (default: ~/.tripitcalb0t/config.yml if it exists and --calendar is not set)")
+
+	fs.DurationVar(&cmd.interval, "interval", time.Minute, "Update interval (ex. 5ms, 10s, 1m, 3h)")
+	fs.BoolVar(&cmd.once, "once", false, "Run once and exit, do not run as a daemon")
+	fs.StringVar(&cmd.listen, "listen", "", "Address to serve /healthz, /readyz and /metrics on (ex. :8080)")
+	fs.DurationVar(&cmd.grace, "grace-period", 30*time.Second, "How long to wait for the writes in progress to finish on ^C or SIGTERM")
+	fs.StringVar(&cmd.stateFile, "state-file", filepath.Join(credsDir, defaultStateFile), "Path to the file to keep the state of the syncs in")
+
+	fs.StringVar(&cmd.sharedMode, "shared", sharedNone, "How to handle trips shared with you that you are not traveling on (none, skip, prefix, calendar)")
+	fs.StringVar(&cmd.sharedCalendarName, "shared-calendar", "", "Calendar name to add events for shared trips to when --shared=calendar")
+}
+
+type syncCommand struct {
+	eventFlags
+
+	configFile string
+
+	interval time.Duration
+	once     bool
+	listen   string
+
+	grace     time.Duration
+	stateFile string
+
+	sharedMode         string
+	sharedCalendarName string
+}
+
+func (cmd *syncCommand) Run(ctx context.Context, args []string) error {
+	if cmd.configFile == "" && calendarName == "" {
+		cmd.configFile = defaultConfigPath()
+	}
+
+	var (
+		c   *config
+		err error
+	)
+	if cmd.configFile != "" {
+		c, err = loadConfig(cmd.configFile, cmd.interval)
+	} else {
+		c, err = cmd.flagsConfig()
+	}
+	if err != nil {
+		return err
+	}
+
+	state, err := loadState(cmd.stateFile)
+	if err != nil {
+		return err
+	}
+
+	syncMetrics.setAccounts(c.Accounts)
+	if cmd.listen != "" {
+		srv := serveMetrics(cmd.listen, syncMetrics)
+		defer srv.Close()
+	}
+
+	// Setup the accounts before we start syncing any of them, so a bad
+	// account fails early.
+	syncers := make([]*accountSyncer, 0, len(c.Accounts))
+	for _, account := range c.Accounts {
+		s, err := newAccountSyncer(ctx, account, state)
+		if err != nil {
+			return fmt.Errorf("account %q: %v", account.Name, err)
+		}
+		syncers = append(syncers, s)
+	}
+
+	// On ^C, or SIGTERM stop starting new syncs and writes. The writes in
+	// progress are aborted after the grace period, or on a second signal.
+	stop, stopCancel := context.WithCancel(ctx)
+	defer stopCancel()
+	abort, abortCancel := context.WithCancel(ctx)
+	defer abortCancel()
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case sig := <-signals:
+			logrus.Infof("Received %s, finishing the writes in progress for at most %s.", sig.String(), cmd.grace)
+			stopCancel()
+		case <-abort.Done():
+			return
+		}
+
+		select {
+		case sig := <-signals:
+			logrus.Infof("Received %s, aborting the writes in progress.", sig.String())
+		case <-time.After(cmd.grace):
+			logrus.Infof("Grace period of %s is over, aborting the writes in progress.", cmd.grace)
+		case <-abort.Done():
+			return
+		}
+		abortCancel()
+	}()
+
+	// Sync each of the accounts on its own schedule.
+	var wg sync.WaitGroup
+	for _, s := range syncers {
+		wg.Add(1)
+		go func(s *accountSyncer) {
+			defer wg.Done()
+			s.loop(stop, abort, cmd.once)
+		}(s)
+	}
+	wg.Wait()
+
+	if err := state.save(); err != nil {
+		logrus.Error(err)
+	}
+
+	// Exit with an error if the last sync of any of the accounts did not complete.
+	var incomplete []string
+	for _, s := range syncers {
+		if s.lastErr != nil {
+			incomplete = append(incomplete, s.account.Name)
+		}
+	}
+	if len(incomplete) > 0 {
+		return fmt.Errorf("the last sync of %s did not complete", strings.Join(incomplete, ", "))
+	}
+
+	return nil
+}
+
+// flagsConfig returns the config for the single account given by the flags.
+func (cmd *syncCommand) flagsConfig() (*config, error) {
+	if err := validateTripItFlags(); err != nil {
+		return nil, err
+	}
+
+	if err := validateCalendarFlags(); err != nil {
+		return nil, err
+	}
+
+	if err := cmd.eventFlags.validate(); err != nil {
+		return nil, err
+	}
+
+	buffers := true
+	target := calendarTarget{
+		Calendar:  calendarName,
+		Past:      cmd.past,
+		Buffers:   &buffers,
+		Shared:    cmd.sharedMode,
+		Attendees: attendeesNone,
+		Privacy:   privacyFull,
+		Kinds:     defaultKinds(),
+		owner:     tripitUsername,
+	}
+	targets := []calendarTarget{target}
+
+	switch cmd.sharedMode {
+	case sharedNone, sharedSkip, sharedPrefix:
+	case sharedCalendar:
+		if len(cmd.sharedCalendarName) < 1 {
+			return nil, errors.New("shared calendar name cannot be empty when --shared=calendar")
+		}
+		targets[0].Shared = sharedSkip
+		target.Calendar = cmd.sharedCalendarName
+		target.Shared = sharedOnly
+		targets = append(targets, target)
+	default:
+		return nil, fmt.Errorf("unknown shared mode %q, must be one of %s, %s, %s, %s", cmd.sharedMode, sharedNone, sharedSkip, sharedPrefix, sharedCalendar)
+	}
+
+	return &config{
+		Accounts: []accountConfig{{
+			Name:           tripitUsername,
+			TripItUsername: tripitUsername,
+			TripItPassword: tripitPassword,
+			GoogleKeyfile:  googleCalendarKeyfile,
+			GoogleToken:    googleTokenFile,
+			GoogleSubject:  googleSubject,
+			Interval:       cmd.interval,
+			Concurrency:    defaultWriteConcurrency,
+			Timezone:       cmd.homeTimezone,
+			AirportsFile:   cmd.airportsFile,
+			Calendars:      targets,
+		}},
+	}, nil
+}
+
+// accountSyncer syncs the events of a TripIt account to its calendars.
+type accountSyncer struct {
+	account accountConfig
+
+	tripitClient *tripit.Client
+	gcalClient   *calendar.Service
+	tz           *tripit.TimezoneResolver
+	writer       *calendarWriter
+
+	state *syncState
+	// lastErr is the error of the last sync, nil if it completed.
+	lastErr error
+	// colorsChecked is set once the colors of the calendars are checked
+	// against the palette of the calendar API.
+	colorsChecked bool
+}
+
+func newAccountSyncer(ctx context.Context, account accountConfig, state *syncState) (*accountSyncer, error) {
+	// Create the Google calendar API client.
+	gcalClient, err := newCalendarClient(ctx, account.googleCredentials())
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the time zone resolver.
+	tz, err := newTimezoneResolver(account.Timezone, account.AirportsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountSyncer{
+		account: account,
+		// Create the TripIt API client.
+		tripitClient: tripit.New(account.TripItUsername, account.TripItPassword, tripit.WithRequestObserver(syncMetrics.observeTripItRequest)),
+		gcalClient:   gcalClient,
+		tz:           tz,
+		writer:       newCalendarWriter(gcalClient, account.Concurrency),
+		state:        state,
+	}, nil
+}
+
+// loop runs the sync, and then on every interval unless once is true. It
+// returns once stop is done, the writes in progress are aborted once abort
+// is done.
+func (s *accountSyncer) loop(stop, abort context.Context, once bool) {
+	if err := s.run(stop, abort); err == nil {
+		logrus.Infof("[%s] Updated TripIt calendar entries in Google calendars %s", s.account.Name, s.calendars())
+	}
+
+	if once {
+		return
+	}
+
+	logrus.Infof("[%s] Starting bot to update TripIt calendar entries in Google calendars %s every %s", s.account.Name, s.calendars(), s.account.Interval)
+	ticker := time.NewTicker(s.account.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// The ticker and stop can be ready at the same time.
+		if stop.Err() != nil {
+			return
+		}
+		s.run(stop, abort)
+	}
+}
+
+func (s *accountSyncer) run(stop, abort context.Context) error {
+	start := time.Now()
+	err := s.sync(stop, abort)
+	s.lastErr = err
+	syncMetrics.observeSync(s.account.Name, time.Since(start), err)
+	s.state.record(s.account.Name, start, err)
+	if err := s.state.save(); err != nil {
+		logrus.Errorf("[%s] %v", s.account.Name, err)
+	}
+	if err != nil {
+		logrus.Errorf("[%s] %v", s.account.Name, err)
+	}
+	return err
+}
+
+// sync syncs the events to all the calendars of the account, it returns an
+// error if any of them failed.
+func (s *accountSyncer) sync(stop, abort context.Context) error {
+	trips, failed, err := getTripItEvents(stop, s.tripitClient, s.tz, s.account.includePast(), s.account.sharedTrips())
+	if err != nil {
+		return fmt.Errorf("getting tripit events failed: %v", err)
+	}
+
+	if !s.colorsChecked {
+		if err := checkColors(stop, s.gcalClient, s.account.Calendars); err != nil {
+			return err
+		}
+		s.colorsChecked = true
+	}
+
+	var failedCalendars int
+	now := time.Now()
+	for _, target := range s.account.Calendars {
+		if stop.Err() != nil {
+			return errors.New("sync was interrupted")
+		}
+
+		stats, err := syncEvents(stop, abort, s.writer, target, s.account.Attendees, target.events(trips, now), failed)
+		syncMetrics.observeEvents(s.account.Name, target.Calendar, stats)
+		s.state.recordCalendar(s.account.Name, target.Calendar, now, stats, err)
+		if err != nil {
+			logrus.Errorf("[%s] %v", s.account.Name, err)
+			failedCalendars++
+		}
+	}
+	if failedCalendars > 0 {
+		return fmt.Errorf("syncing %d of %d calendars failed", failedCalendars, len(s.account.Calendars))
+	}
+
+	return nil
+}
+
+func (s *accountSyncer) calendars() string {
+	names := make([]string, 0, len(s.account.Calendars))
+	for _, target := range s.account.Calendars {
+		names = append(names, target.Calendar)
+	}
+	return strings.Join(names, ", ")
+}
+
+// syncStats holds the number of events written to a calendar by a sync.
+type syncStats struct {
+	Created int
+	Updated int
+	Deleted int
+	// Failed is the number of writes that failed.
+	Failed int
+}
+
+// syncEvents creates or patches the events for the trips in the calendar of
+// the target, emails maps the travelers to the email addresses of the
+// attendees. The events with the keys in keep are never pruned, they are in
+// TripIt but could not be made. The writes are planned first and then done
+// by the writer in parallel. No more writes are started once ctx is done,
+// the writes use writeCtx so the writes in progress can finish after that.
+func syncEvents(ctx, writeCtx context.Context, writer *calendarWriter, target calendarTarget, emails map[string]string, trips []tripit.Event, keep []string) (syncStats, error) {
+	var stats syncStats
+	calendarName := target.Calendar
+	gcalClient := writer.client
+
+	// Get a list of events from Google calendar, the ones we synced before
+	// and the ones from before the events had keys, that have "Flight" in
+	// their description.
+	t := time.Now().AddDate(-4, 0, 0).Format(time.RFC3339)
+	var events []*calendar.Event
+	seen := map[string]bool{}
+	addPage := func(page *calendar.Events) error {
+		for _, e := range page.Items {
+			if !seen[e.Id] {
+				seen[e.Id] = true
+				events = append(events, e)
+			}
+		}
+		return nil
+	}
+	err := gcalClient.Events.List(calendarName).ShowDeleted(false).SingleEvents(true).TimeMin(t).OrderBy("updated").PrivateExtendedProperty(eventOwnerProperty+"="+target.owner).MaxResults(2500).Pages(ctx, addPage)
+	if err == nil {
+		err = gcalClient.Events.List(calendarName).ShowDeleted(false).SingleEvents(true).TimeMin(t).OrderBy("updated").Q("Flight").MaxResults(2500).Pages(ctx, addPage)
+	}
+	if err != nil {
+		return stats, fmt.Errorf("getting events from google calendar %s failed: %v", calendarName, err)
+	}
+
+	// Iterate over the trip and see if we already have a matching calendar event.
+	// If not make one and/or update the old one.
+	var writes []calendarWrite
+	for _, trip := range trips {
+		if trip.ConfirmationNumber == "" && (trip.Kind == tripit.EventKindFlight || trip.Kind == tripit.EventKindBuffer) {
+			logrus.Warnf("skipping trip that has no confirmation number: %#v", trip)
+			continue
+		}
+
+		var matchingEvent *calendar.Event
+		for _, e := range events {
+			// The events from before they had an owner are the account's,
+			// their calendar was only synced by it.
+			owner := eventOwner(e)
+			if trip.Key != "" && eventKey(e) == trip.Key && (owner == target.owner || owner == "") {
+				matchingEvent = e
+				break
+			}
+		}
+		if matchingEvent == nil {
+			for _, e := range events {
+				// We only care about TripIt events that match our tripID or segmentID.
+				if eventKey(e) == "" && trip.SegmentID != "" && trip.Title == e.Summary &&
+					strings.Contains(e.Description, trip.SegmentID) {
+					matchingEvent = e
+					break
+				}
+			}
+		}
+
+		// Get airport information, or the address of the events that are
+		// not at an airport.
+		location := eventLocation(trip)
+
+		// The writes are done after the loop, so they get their own copy of
+		// the times rather than pointers to the loop variable.
+		start, end := trip.Start, trip.End
+
+		// Only the flights have attendees, the buffers are the travel time
+		// of the user.
+		var attendees []string
+		if target.Attendees == attendeesInvite && trip.Kind == tripit.EventKindFlight {
+			attendees = attendeeEmails(trip, emails, calendarName)
+		}
+
+		if matchingEvent == nil {
+			if target.Attendees == attendeesDedupe && trip.Kind == tripit.EventKindFlight && isInvitedDuplicate(trip, events) {
+				logrus.Infof("skipping %q in google calendar %s, a co-traveler already invited it", trip.Title, calendarName)
+				continue
+			}
+
+			// No event was found for this trip, let's create one.
+			matchingEvent = &calendar.Event{
+				Summary:     trip.Title,
+				Description: trip.Description,
+				Start:       &start,
+				End:         &end,
+				Location:    location,
+				ColorId:     trip.ColorID,
+				Reminders:   target.eventReminders(trip.Kind),
+			}
+			addAttendees(matchingEvent, attendees)
+			target.privacy.setEventPrivacy(matchingEvent)
+			setEventKey(matchingEvent, target.owner, trip.Key)
+			setOwnedFields(matchingEvent)
+
+			writes = append(writes, calendarWrite{op: writeInsert, title: trip.Title, event: matchingEvent})
+			continue
+		}
+
+		// Patch the fields of our matching event that changed.
+		want := &calendar.Event{
+			Summary:     trip.Title,
+			Description: trip.Description,
+			Start:       &start,
+			End:         &end,
+			Location:    location,
+			ColorId:     trip.ColorID,
+			Reminders:   target.eventReminders(trip.Kind),
+		}
+		addAttendees(want, attendees)
+		target.privacy.setEventPrivacy(want)
+		setEventKey(want, target.owner, trip.Key)
+		setOwnedFields(want)
+
+		patch, changed := eventPatch(matchingEvent, want)
+		if !changed {
+			logrus.Debugf("%q in google calendar %s is up to date", trip.Title, calendarName)
+			continue
+		}
+		writes = append(writes, calendarWrite{op: writePatch, id: matchingEvent.Id, title: trip.Title, event: patch})
+	}
+
+	if target.Prune {
+		writes = append(writes, pruneWrites(target, events, trips, keep)...)
+	}
+
+	return writer.write(ctx, writeCtx, calendarName, writes)
+}
+
+// pruneWrites returns the deletes of the events the account synced to the
+// calendar before that have not ended and are not for any of the trips
+// anymore, or in keep. The events from before the events had keys are left
+// alone, they could be anyone's.
+func pruneWrites(target calendarTarget, events []*calendar.Event, trips []tripit.Event, keep []string) []calendarWrite {
+	keys := map[string]bool{}
+	for _, trip := range trips {
+		keys[trip.Key] = true
+	}
+	for _, key := range keep {
+		keys[key] = true
+	}
+
+	var writes []calendarWrite
+	now := time.Now()
+	for _, e := range events {
+		key := eventKey(e)
+		if key == "" || keys[key] || eventOwner(e) != target.owner || e.End == nil {
+			continue
+		}
+		if end, err := eventEnd(*e.End); err != nil || end.Before(now) {
+			continue
+		}
+
+		logrus.Infof("deleting %q from google calendar %s, it is not in TripIt anymore", e.Summary, target.Calendar)
+		writes = append(writes, calendarWrite{op: writeDelete, id: e.Id, title: e.Summary})
+	}
+	return writes
+}
+
+// setEventKey sets the key of the TripIt event and the account that synced
+// it on the calendar event.
+func setEventKey(event *calendar.Event, owner, key string) {
+	if event.ExtendedProperties == nil {
+		event.ExtendedProperties = &calendar.EventExtendedProperties{}
+	}
+	if event.ExtendedProperties.Private == nil {
+		event.ExtendedProperties.Private = map[string]string{}
+	}
+	event.ExtendedProperties.Private[eventOwnerProperty] = owner
+	event.ExtendedProperties.Private[eventKeyProperty] = key
+}
+
+// setOwnedFields records the reminders, visibility and transparency we set
+// on the calendar event in its private extended properties, the ones we do
+// not set are left out. setEventKey has to be called first.
+func setOwnedFields(event *calendar.Event) {
+	for key, value := range map[string]string{
+		eventRemindersProperty:    remindersHash(event.Reminders),
+		eventVisibilityProperty:   event.Visibility,
+		eventTransparencyProperty: event.Transparency,
+	} {
+		if value != "" {
+			event.ExtendedProperties.Private[key] = value
+		}
+	}
+}
+
+// eventProperty returns the private extended property of the calendar
+// event, or an empty string if it has none.
+func eventProperty(event *calendar.Event, key string) string {
+	if event.ExtendedProperties == nil {
+		return ""
+	}
+	return event.ExtendedProperties.Private[key]
+}
+
+// eventKey returns the key of the TripIt event of the calendar event, or
+// an empty string if it was created before the events had keys.
+func eventKey(event *calendar.Event) string {
+	if event.ExtendedProperties == nil {
+		return ""
+	}
+	return event.ExtendedProperties.Private[eventKeyProperty]
+}
+
+// eventOwner returns the TripIt username of the account that synced the
+// calendar event.
+func eventOwner(event *calendar.Event) string {
+	if event.ExtendedProperties == nil {
+		return ""
+	}
+	return event.ExtendedProperties.Private[eventOwnerProperty]
+}
@@ -0,0 +1,560 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/calendartest"
+	"github.com/jessfraz/tripitcalb0t/tripit"
+	"github.com/jessfraz/tripitcalb0t/tripit/tripittest"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+const testCalendar = "traveler@example.com"
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// testTarget returns the target for the calendar with the defaults of the
+// config file.
+func testTarget(t *testing.T, target calendarTarget) calendarTarget {
+	t.Helper()
+
+	a := accountConfig{
+		TripItUsername: tripittest.DefaultUsername,
+		Calendars:      []calendarTarget{target},
+	}
+	a.setDefaults(time.Minute)
+	if err := a.setPrivacyProfiles(nil); err != nil {
+		t.Fatal(err)
+	}
+	return a.Calendars[0]
+}
+
+// testWriter returns a writer for the server that does not wait between the
+// writes or back off from the errors like it does for the real API.
+func testWriter(cs *calendartest.Server) *calendarWriter {
+	w := newCalendarWriter(cs.Service(), defaultWriteConcurrency)
+	w.limiter = newAdaptiveLimiter(0, time.Millisecond)
+	w.backoff = time.Millisecond
+	return w
+}
+
+// testFlights returns n flight events a day apart, each with its own times.
+func testFlights(n int) []tripit.Event {
+	var events []tripit.Event
+	start := time.Date(2030, time.March, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		s := start.AddDate(0, 0, i)
+		events = append(events, tripit.Event{
+			Kind:               tripit.EventKindFlight,
+			Title:              fmt.Sprintf("Flight UA %d", 100+i),
+			Description:        fmt.Sprintf("Flight UA %d", 100+i),
+			Start:              calendar.EventDateTime{DateTime: s.Format(time.RFC3339), TimeZone: "UTC"},
+			End:                calendar.EventDateTime{DateTime: s.Add(3 * time.Hour).Format(time.RFC3339), TimeZone: "UTC"},
+			ID:                 fmt.Sprintf("trip%d", i),
+			SegmentID:          fmt.Sprintf("segment%d", i),
+			Key:                fmt.Sprintf("segment%d", i),
+			ConfirmationNumber: "ABC123",
+		})
+	}
+	return events
+}
+
+func countRequests(cs *calendartest.Server, operation string) int {
+	var n int
+	for _, r := range cs.Requests() {
+		if r.Operation == operation {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSyncEventsTimes(t *testing.T) {
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	ctx := context.Background()
+	target := testTarget(t, calendarTarget{Calendar: testCalendar})
+	trips := testFlights(3)
+
+	stats, err := syncEvents(ctx, ctx, testWriter(cs), target, nil, trips, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Created != len(trips) {
+		t.Fatalf("created %d events, want %d", stats.Created, len(trips))
+	}
+
+	// The writes are done after all the trips are planned, each event has
+	// to keep the times of its own trip.
+	starts := map[string]string{}
+	for _, e := range cs.Events(testCalendar) {
+		starts[e.Summary] = e.Start.DateTime
+		if e.End == nil || e.End.DateTime <= e.Start.DateTime {
+			t.Errorf("%q ends at %v, before it starts at %s", e.Summary, e.End, e.Start.DateTime)
+		}
+	}
+	for _, trip := range trips {
+		if starts[trip.Title] != trip.Start.DateTime {
+			t.Errorf("%q starts at %q, want %q", trip.Title, starts[trip.Title], trip.Start.DateTime)
+		}
+	}
+
+	// Changing the times of all the trips patches each to its own times.
+	for i := range trips {
+		s, _ := time.Parse(time.RFC3339, trips[i].Start.DateTime)
+		trips[i].Start.DateTime = s.Add(time.Hour).Format(time.RFC3339)
+		trips[i].End.DateTime = s.Add(4 * time.Hour).Format(time.RFC3339)
+	}
+	stats, err = syncEvents(ctx, ctx, testWriter(cs), target, nil, trips, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Updated != len(trips) {
+		t.Fatalf("updated %d events, want %d", stats.Updated, len(trips))
+	}
+	for _, e := range cs.Events(testCalendar) {
+		starts[e.Summary] = e.Start.DateTime
+	}
+	for _, trip := range trips {
+		if starts[trip.Title] != trip.Start.DateTime {
+			t.Errorf("%q starts at %q after the patch, want %q", trip.Title, starts[trip.Title], trip.Start.DateTime)
+		}
+	}
+}
+
+func TestSyncEventsIdempotent(t *testing.T) {
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	ctx := context.Background()
+	target := testTarget(t, calendarTarget{
+		Calendar:  testCalendar,
+		Attendees: attendeesInvite,
+		Reminders: map[tripit.EventKind][]reminderRule{
+			tripit.EventKindFlight: {{Before: 2 * time.Hour}},
+		},
+	})
+	trips := testFlights(3)
+	for i := range trips {
+		trips[i].Travelers = []string{"Alex Traveler"}
+	}
+	emails := map[string]string{normalizeAttendeeKey("Alex Traveler"): "alex@example.com"}
+
+	if _, err := syncEvents(ctx, ctx, testWriter(cs), target, emails, trips, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing changed in TripIt, so the second sync has nothing to write.
+	stats, err := syncEvents(ctx, ctx, testWriter(cs), target, emails, trips, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (syncStats{}) {
+		t.Errorf("second sync wrote %+v, want nothing", stats)
+	}
+	if n := countRequests(cs, "patch"); n != 0 {
+		t.Errorf("second sync sent %d patches, want 0", n)
+	}
+}
+
+func TestSyncOwnedFields(t *testing.T) {
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	ctx := context.Background()
+	target := testTarget(t, calendarTarget{
+		Calendar: testCalendar,
+		Reminders: map[tripit.EventKind][]reminderRule{
+			tripit.EventKindFlight: {{Method: reminderPopup, Before: 2 * time.Hour}},
+		},
+	})
+	target.privacy = privacyProfile{Visibility: "private", Transparency: transparencyFree}
+	trips := testFlights(2)
+
+	if _, err := syncEvents(ctx, ctx, testWriter(cs), target, nil, trips, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The user changes the reminders and the visibility of the first flight.
+	events := map[string]*calendar.Event{}
+	for _, e := range cs.Events(testCalendar) {
+		events[e.Summary] = e
+	}
+	edited := events[trips[0].Title]
+	_, err := cs.Service().Events.Patch(testCalendar, edited.Id, &calendar.Event{
+		Visibility: "public",
+		Reminders: &calendar.EventReminders{
+			Overrides:       []*calendar.EventReminder{{Method: reminderEmail, Minutes: 30}},
+			ForceSendFields: []string{"UseDefault"},
+		},
+	}).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The calendar changes its reminders and no longer has a privacy
+	// profile.
+	target.Reminders[tripit.EventKindFlight] = []reminderRule{{Method: reminderPopup, Before: time.Hour}}
+	target.privacy = privacyProfile{}
+	if _, err := syncEvents(ctx, ctx, testWriter(cs), target, nil, trips, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range cs.Events(testCalendar) {
+		events[e.Summary] = e
+	}
+	tests := []struct {
+		title        string
+		reminder     string
+		visibility   string
+		transparency string
+	}{
+		// The changes of the user are kept.
+		{trips[0].Title, "email:30", "public", "opaque"},
+		// The fields we set follow the calendar.
+		{trips[1].Title, "popup:60", "default", "opaque"},
+	}
+	for _, tt := range tests {
+		e := events[tt.title]
+		if got := strings.Join(reminderOverrides(e.Reminders), ","); got != tt.reminder {
+			t.Errorf("%q has the reminders %q, want %q", tt.title, got, tt.reminder)
+		}
+		if e.Visibility != tt.visibility || e.Transparency != tt.transparency {
+			t.Errorf("%q has the visibility %q and transparency %q, want %q and %q", tt.title, e.Visibility, e.Transparency, tt.visibility, tt.transparency)
+		}
+	}
+
+	// Nothing changed, so the next sync has nothing to write.
+	stats, err := syncEvents(ctx, ctx, testWriter(cs), target, nil, trips, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (syncStats{}) {
+		t.Errorf("last sync wrote %+v, want nothing", stats)
+	}
+}
+
+// newTestSyncer returns the syncer of an account with the target that syncs
+// from the TripIt fake to the calendar fake.
+func newTestSyncer(t *testing.T, ts *tripittest.Server, cs *calendartest.Server, target calendarTarget) *accountSyncer {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "tripitcalb0t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	state, err := loadState(filepath.Join(dir, defaultStateFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tz, err := newTimezoneResolver("UTC", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target = testTarget(t, target)
+	account := accountConfig{
+		Name:           tripittest.DefaultUsername,
+		TripItUsername: tripittest.DefaultUsername,
+		Timezone:       "UTC",
+		Calendars:      []calendarTarget{target},
+	}
+	account.setDefaults(time.Minute)
+	account.Calendars = []calendarTarget{target}
+
+	return &accountSyncer{
+		account:      account,
+		tripitClient: ts.TripItClient(),
+		gcalClient:   cs.Service(),
+		tz:           tz,
+		writer:       testWriter(cs),
+		state:        state,
+	}
+}
+
+// testSegment returns a flight segment on the day, the times are in UTC.
+func testSegment(from, to, number, date, start, end string) tripit.FlightSegment {
+	return tripit.FlightSegment{
+		StartAirportCode:      from,
+		StartCityName:         from,
+		EndAirportCode:        to,
+		EndCityName:           to,
+		MarketingAirline:      "United",
+		MarketingAirlineCode:  "UA",
+		MarketingFlightNumber: number,
+		StartDateTime:         tripit.DateTime{Date: date, Time: start, Timezone: "UTC", UTCOffset: "+00:00"},
+		EndDateTime:           tripit.DateTime{Date: date, Time: end, Timezone: "UTC", UTCOffset: "+00:00"},
+	}
+}
+
+// checkGolden compares the events of the calendar to the golden file, or
+// updates it with -update.
+func checkGolden(t *testing.T, cs *calendartest.Server, name string) {
+	t.Helper()
+
+	got, err := cs.Dump(testCalendar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "sync", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("events of %s differ from %s, run go test -update if the change is expected\ngot:\n%s", testCalendar, golden, got)
+	}
+}
+
+func TestSyncGolden(t *testing.T) {
+	ts := tripittest.NewServer()
+	defer ts.Close()
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	newYork := ts.AddTrip(tripit.Trip{DisplayName: "New York", StartDate: "2030-03-01", EndDate: "2030-03-05"})
+	flight := ts.AddFlight(tripit.Flight{
+		TripID:           newYork.ID,
+		SupplierConfNum:  "ABC123",
+		IsClientTraveler: true,
+		Segments: tripit.FlightSegments{
+			testSegment("SFO", "JFK", "100", "2030-03-01", "08:00:00", "13:30:00"),
+			testSegment("JFK", "SFO", "101", "2030-03-05", "17:00:00", "23:45:00"),
+		},
+	})
+	chicago := ts.AddTrip(tripit.Trip{DisplayName: "Chicago", StartDate: "2030-04-10", EndDate: "2030-04-12"})
+	ts.AddFlight(tripit.Flight{
+		TripID:           chicago.ID,
+		SupplierConfNum:  "XYZ789",
+		IsClientTraveler: true,
+		Segments: tripit.FlightSegments{
+			testSegment("SFO", "ORD", "200", "2030-04-10", "07:00:00", "11:15:00"),
+		},
+	})
+
+	s := newTestSyncer(t, ts, cs, calendarTarget{
+		Calendar: testCalendar,
+		Kinds:    []tripit.EventKind{tripit.EventKindFlight, tripit.EventKindBuffer, tripit.EventKindTrip},
+		Prune:    true,
+	})
+	ctx := context.Background()
+	tripitClient := ts.TripItClient()
+
+	steps := []struct {
+		name string
+		// change changes the trips in TripIt before the sync.
+		change func() error
+		// writes are the operations the sync is expected to send.
+		writes map[string]bool
+	}{
+		{
+			name:   "first-run",
+			change: func() error { return nil },
+			writes: map[string]bool{"insert": true},
+		},
+		{
+			name:   "second-run",
+			change: func() error { return nil },
+			writes: map[string]bool{},
+		},
+		{
+			name: "changed-flight",
+			change: func() error {
+				id := flight.Segments[0].ID
+				flight.Segments[0] = testSegment("SFO", "JFK", "102", "2030-03-01", "10:00:00", "15:30:00")
+				flight.Segments[0].ID = id
+				_, err := tripitClient.ReplaceFlight(ctx, flight.ID, flight)
+				return err
+			},
+			writes: map[string]bool{"patch": true},
+		},
+		{
+			name:   "deleted-trip",
+			change: func() error { return tripitClient.DeleteTrip(ctx, chicago.ID) },
+			writes: map[string]bool{"delete": true},
+		},
+	}
+	for _, step := range steps {
+		if err := step.change(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		before := len(cs.Requests())
+		if err := s.sync(ctx, ctx); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		sent := map[string]bool{}
+		for _, r := range cs.Requests()[before:] {
+			switch r.Operation {
+			case "insert", "patch", "delete":
+				sent[r.Operation] = true
+				if !step.writes[r.Operation] {
+					t.Errorf("%s: unexpected %s of %q", step.name, r.Operation, r.EventID)
+				}
+			}
+		}
+		for op := range step.writes {
+			if !sent[op] {
+				t.Errorf("%s: no %s was sent", step.name, op)
+			}
+		}
+		checkGolden(t, cs, step.name)
+	}
+}
+
+func TestSyncPruneKeepsUnconvertedFlights(t *testing.T) {
+	ts := tripittest.NewServer()
+	defer ts.Close()
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	trip := ts.AddTrip(tripit.Trip{DisplayName: "New York", StartDate: "2030-03-01", EndDate: "2030-03-01"})
+	flight := ts.AddFlight(tripit.Flight{
+		TripID:           trip.ID,
+		SupplierConfNum:  "ABC123",
+		IsClientTraveler: true,
+		Segments: tripit.FlightSegments{
+			testSegment("SFO", "JFK", "100", "2030-03-01", "08:00:00", "13:30:00"),
+		},
+	})
+
+	s := newTestSyncer(t, ts, cs, calendarTarget{Calendar: testCalendar, Prune: true})
+	ctx := context.Background()
+	if err := s.sync(ctx, ctx); err != nil {
+		t.Fatal(err)
+	}
+	synced := len(cs.Events(testCalendar))
+	if synced != len(flight.EventKeys()) {
+		t.Fatalf("synced %d events, want %d", synced, len(flight.EventKeys()))
+	}
+
+	// The flight is still in TripIt, it just cannot be turned into events
+	// without its departure time, so its events have to stay.
+	flight.Segments[0].StartDateTime.Time = ""
+	if _, err := ts.TripItClient().ReplaceFlight(ctx, flight.ID, flight); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.sync(ctx, ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRequests(cs, "delete"); n != 0 {
+		t.Errorf("sync sent %d deletes for the flight that could not be converted, want 0", n)
+	}
+	if n := len(cs.Events(testCalendar)); n != synced {
+		t.Errorf("calendar has %d events, want %d", n, synced)
+	}
+}
+
+// testEvent returns a calendar event with the summary.
+func testEvent(summary string) calendar.Event {
+	return calendar.Event{
+		Summary: summary,
+		Start:   &calendar.EventDateTime{DateTime: "2030-03-01T09:00:00Z"},
+		End:     &calendar.EventDateTime{DateTime: "2030-03-01T12:00:00Z"},
+	}
+}
+
+func TestSyncObjectKinds(t *testing.T) {
+	ts := tripittest.NewServer()
+	defer ts.Close()
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	trip := ts.AddTrip(tripit.Trip{DisplayName: "New York", StartDate: "2030-03-01", EndDate: "2030-03-05"})
+	ts.AddObject(tripit.TypeLodging, tripit.Lodging{
+		TripID:          trip.ID,
+		SupplierName:    "Example Hotel",
+		SupplierConfNum: "HOTEL1",
+		StartDateTime:   tripit.DateTime{Date: "2030-03-01", Time: "15:00:00", Timezone: "America/New_York", UTCOffset: "-05:00"},
+		EndDateTime:     tripit.DateTime{Date: "2030-03-05", Time: "11:00:00", Timezone: "America/New_York", UTCOffset: "-05:00"},
+		Address:         tripit.Address{Address: "1 Main St, New York, NY"},
+	})
+	ts.AddObject(tripit.TypeRail, tripit.Rail{
+		TripID:          trip.ID,
+		SupplierConfNum: "RAIL1",
+		Segments: tripit.RailSegments{{
+			StartDateTime:    tripit.DateTime{Date: "2030-03-03", Time: "09:00:00", Timezone: "America/New_York", UTCOffset: "-05:00"},
+			EndDateTime:      tripit.DateTime{Date: "2030-03-03", Time: "12:30:00", Timezone: "America/New_York", UTCOffset: "-05:00"},
+			StartStationName: "New York Penn Station",
+			EndStationName:   "Boston South Station",
+			CarrierName:      "Amtrak",
+			TrainNumber:      "2150",
+		}},
+	})
+	ts.AddObject(tripit.TypeCar, tripit.Car{
+		TripID:          trip.ID,
+		SupplierName:    "Example Cars",
+		SupplierConfNum: "CAR1",
+		StartDateTime:   tripit.DateTime{Date: "2030-03-02", Time: "10:00:00", Timezone: "America/New_York", UTCOffset: "-05:00"},
+		EndDateTime:     tripit.DateTime{Date: "2030-03-02", Time: "18:00:00", Timezone: "America/New_York", UTCOffset: "-05:00"},
+	})
+
+	// Hotels and trains go to the calendar, with an email the day before
+	// the check-in, the cars do not.
+	s := newTestSyncer(t, ts, cs, calendarTarget{
+		Calendar: testCalendar,
+		Kinds:    []tripit.EventKind{tripit.EventKindLodging, tripit.EventKindRail},
+		Colors:   map[tripit.EventKind]string{tripit.EventKindLodging: "basil"},
+		Reminders: map[tripit.EventKind][]reminderRule{
+			tripit.EventKindLodging: {{Method: reminderEmail, Before: 24 * time.Hour}},
+		},
+	})
+	ctx := context.Background()
+	if err := s.sync(ctx, ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	events := map[string]*calendar.Event{}
+	for _, e := range cs.Events(testCalendar) {
+		events[e.Summary] = e
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want the hotel and the train", len(events))
+	}
+
+	hotel, ok := events["Stay at Example Hotel"]
+	if !ok {
+		t.Fatalf("no event for the hotel in %v", events)
+	}
+	if hotel.Start.DateTime != "2030-03-01T15:00:00-05:00" || hotel.End.DateTime != "2030-03-05T11:00:00-05:00" {
+		t.Errorf("hotel is from %s to %s, want from the check-in to the check-out", hotel.Start.DateTime, hotel.End.DateTime)
+	}
+	if hotel.Location != "1 Main St, New York, NY" {
+		t.Errorf("hotel location is %q, want its address", hotel.Location)
+	}
+	if hotel.ColorId != "10" {
+		t.Errorf("hotel color is %q, want basil", hotel.ColorId)
+	}
+	if hotel.Reminders == nil || len(hotel.Reminders.Overrides) != 1 ||
+		hotel.Reminders.Overrides[0].Method != reminderEmail || hotel.Reminders.Overrides[0].Minutes != 24*60 {
+		t.Errorf("hotel reminders are %+v, want an email a day before the check-in", hotel.Reminders)
+	}
+
+	if _, ok := events["Train to Boston South Station (Amtrak 2150)"]; !ok {
+		t.Errorf("no event for the train in %v", events)
+	}
+}
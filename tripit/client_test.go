@@ -0,0 +1,382 @@
+package tripit_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+	"github.com/jessfraz/tripitcalb0t/tripit/tripittest"
+)
+
+var formats = []tripit.Format{tripit.FormatJSON, tripit.FormatXML}
+
+// forEachFormat runs the test against a new server with a client in each of
+// the wire formats.
+func forEachFormat(t *testing.T, test func(t *testing.T, s *tripittest.Server, c *tripit.Client)) {
+	for _, format := range formats {
+		t.Run(string(format), func(t *testing.T) {
+			s := tripittest.NewServer()
+			defer s.Close()
+
+			test(t, s, s.TripItClient(tripit.WithFormat(format)))
+		})
+	}
+}
+
+// rawGet returns the body the server sends for the endpoint in the format.
+func rawGet(t *testing.T, s *tripittest.Server, endpoint string, format tripit.Format) string {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, s.URL+"/"+tripit.APIVersion+"/"+endpoint+"/format/"+string(format), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetBasicAuth(s.Username, s.Password)
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestClientPaging(t *testing.T) {
+	forEachFormat(t, func(t *testing.T, s *tripittest.Server, c *tripit.Client) {
+		for i := 0; i < 12; i++ {
+			s.AddTrip(tripit.Trip{DisplayName: "Upcoming", StartDate: "2999-01-01", EndDate: "2999-01-02"})
+		}
+		for i := 0; i < 3; i++ {
+			s.AddTrip(tripit.Trip{DisplayName: "Past", StartDate: "2000-01-01", EndDate: "2000-01-02"})
+		}
+
+		tests := []struct {
+			name        string
+			includePast bool
+			pageSize    int
+			wantTrips   int
+			wantPages   int
+		}{
+			{name: "upcoming", wantTrips: 12, pageSize: 5, wantPages: 3},
+			{name: "one page", wantTrips: 12, pageSize: 25, wantPages: 1},
+			{name: "exact pages", wantTrips: 12, pageSize: 6, wantPages: 2},
+			// The past trips are a page of their own.
+			{name: "past", includePast: true, wantTrips: 15, pageSize: 5, wantPages: 4},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				it := c.TripIterator(tt.includePast)
+				it.PageSize = tt.pageSize
+
+				seen := map[string]bool{}
+				var pages int
+				err := it.ForEachPage(context.Background(), func(resp *tripit.Response) error {
+					pages++
+					if len(resp.Trips) > tt.pageSize {
+						t.Errorf("page %d has %d trips, more than the page size %d", pages, len(resp.Trips), tt.pageSize)
+					}
+					for _, trip := range resp.Trips {
+						if seen[trip.ID] {
+							t.Errorf("trip %s is on more than one page", trip.ID)
+						}
+						seen[trip.ID] = true
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(seen) != tt.wantTrips {
+					t.Errorf("got %d trips, want %d", len(seen), tt.wantTrips)
+				}
+				if pages != tt.wantPages {
+					t.Errorf("got %d pages, want %d", pages, tt.wantPages)
+				}
+
+				if _, err := it.Next(context.Background()); err != tripit.ErrIteratorDone {
+					t.Errorf("Next after the last page returned %v, want ErrIteratorDone", err)
+				}
+			})
+		}
+	})
+}
+
+func TestClientSingleAndMany(t *testing.T) {
+	forEachFormat(t, func(t *testing.T, s *tripittest.Server, c *tripit.Client) {
+		ctx := context.Background()
+		trip := s.AddTrip(tripit.Trip{DisplayName: "New York", StartDate: "2999-01-01", EndDate: "2999-01-02"})
+
+		resp, err := c.ListTrips(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Trips) != 1 || resp.Trips[0].ID != trip.ID {
+			t.Errorf("got trips %+v, want only %s", resp.Trips, trip.ID)
+		}
+
+		s.AddTrip(tripit.Trip{DisplayName: "Boston", StartDate: "2999-02-01", EndDate: "2999-02-02"})
+		resp, err = c.ListTrips(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Trips) != 2 {
+			t.Errorf("got %d trips, want 2", len(resp.Trips))
+		}
+
+		// A flight with one segment, and one with many.
+		one := s.AddFlight(tripit.Flight{TripID: trip.ID, Segments: tripit.FlightSegments{{StartAirportCode: "SFO", EndAirportCode: "JFK"}}})
+		many := s.AddFlight(tripit.Flight{TripID: trip.ID, Segments: tripit.FlightSegments{
+			{StartAirportCode: "SFO", EndAirportCode: "JFK"},
+			{StartAirportCode: "JFK", EndAirportCode: "BOS"},
+		}})
+		for _, want := range []tripit.Flight{one, many} {
+			got, err := c.GetFlight(ctx, want.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Segments) != len(want.Segments) {
+				t.Errorf("flight %s has %d segments, want %d", want.ID, len(got.Segments), len(want.Segments))
+			}
+			for i := range want.Segments {
+				if i < len(got.Segments) && got.Segments[i].ID != want.Segments[i].ID {
+					t.Errorf("segment %d of flight %s is %s, want %s", i, want.ID, got.Segments[i].ID, want.Segments[i].ID)
+				}
+			}
+		}
+	})
+}
+
+func TestServerSendsSingleObject(t *testing.T) {
+	s := tripittest.NewServer()
+	defer s.Close()
+
+	s.AddTrip(tripit.Trip{DisplayName: "New York", StartDate: "2999-01-01", EndDate: "2999-01-02"})
+	if body := rawGet(t, s, "list/trip", tripit.FormatJSON); !strings.Contains(body, `"Trip":{`) {
+		t.Errorf("a single trip is not an object in %s", body)
+	}
+
+	s.AddTrip(tripit.Trip{DisplayName: "Boston", StartDate: "2999-02-01", EndDate: "2999-02-02"})
+	if body := rawGet(t, s, "list/trip", tripit.FormatJSON); !strings.Contains(body, `"Trip":[`) {
+		t.Errorf("two trips are not an array in %s", body)
+	}
+}
+
+func TestClientAttributes(t *testing.T) {
+	forEachFormat(t, func(t *testing.T, s *tripittest.Server, c *tripit.Client) {
+		ctx := context.Background()
+
+		profile, err := c.GetCurrentProfile(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if profile.Attributes.Ref != "PROFILEREF" {
+			t.Errorf("got profile ref %q, want PROFILEREF", profile.Attributes.Ref)
+		}
+
+		trip := s.AddTrip(tripit.Trip{
+			DisplayName: "New York",
+			StartDate:   "2999-01-01",
+			EndDate:     "2999-01-02",
+			Invitees: tripit.Invitees{
+				{IsTraveler: true, Attributes: tripit.InviteeAttributes{ProfileRef: "PROFILEREF"}},
+				{IsReadOnly: true, Attributes: tripit.InviteeAttributes{ProfileRef: "VIEWERREF"}},
+			},
+			ClosenessMatches: tripit.ClosenessMatches{ClosenessMatches: []tripit.ClosenessMatch{
+				{Attributes: tripit.ClosenessMatchAttributes{ProfileRef: "NEARREF"}},
+			}},
+		})
+
+		participants, err := c.ListTripParticipants(ctx, trip.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var refs []string
+		for _, p := range participants {
+			refs = append(refs, p.Invitee.Attributes.ProfileRef)
+		}
+		if got, want := strings.Join(refs, ","), "PROFILEREF,VIEWERREF"; got != want {
+			t.Errorf("got participants %s, want %s", got, want)
+		}
+
+		got, err := c.GetTrip(ctx, trip.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if matches := got.ClosenessMatches.ClosenessMatches; len(matches) != 1 || matches[0].Attributes.ProfileRef != "NEARREF" {
+			t.Errorf("got closeness matches %+v, want NEARREF", matches)
+		}
+	})
+}
+
+func TestServerSendsAttributes(t *testing.T) {
+	s := tripittest.NewServer()
+	defer s.Close()
+
+	if body := rawGet(t, s, "get/profile", tripit.FormatJSON); !strings.Contains(body, `"@attributes":{"ref":"PROFILEREF"}`) {
+		t.Errorf("no @attributes in %s", body)
+	}
+	if body := rawGet(t, s, "get/profile", tripit.FormatXML); !strings.Contains(body, `<Profile ref="PROFILEREF">`) {
+		t.Errorf("no ref attribute in %s", body)
+	}
+}
+
+func TestClientFaults(t *testing.T) {
+	codes := []int{
+		http.StatusBadRequest,
+		http.StatusForbidden,
+		http.StatusNotFound,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+	}
+
+	forEachFormat(t, func(t *testing.T, s *tripittest.Server, c *tripit.Client) {
+		ctx := context.Background()
+
+		for _, code := range codes {
+			s.Fail("list/trip", code, 1)
+
+			_, err := c.ListTrips(ctx)
+			if err == nil {
+				t.Fatalf("ListTrips did not fail with the injected %d", code)
+			}
+			if !strings.Contains(err.Error(), fmt.Sprintf("returned status code %d", code)) {
+				t.Errorf("error %q does not have the status code %d", err, code)
+			}
+
+			// Only the next request fails.
+			if _, err := c.ListTrips(ctx); err != nil {
+				t.Errorf("ListTrips after the injected %d failed: %v", code, err)
+			}
+		}
+
+		// The faults only match their endpoints.
+		s.Fail("get/air", http.StatusInternalServerError, -1)
+		if _, err := c.GetCurrentProfile(ctx); err != nil {
+			t.Errorf("GetCurrentProfile failed with a fault on get/air: %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			if _, err := c.GetFlight(ctx, "1"); err == nil || !strings.Contains(err.Error(), "status code 500") {
+				t.Errorf("GetFlight returned %v, want a 500", err)
+			}
+		}
+
+		s.Recover()
+		if _, err := c.GetFlight(ctx, "1"); err == nil || !strings.Contains(err.Error(), "status code 404") {
+			t.Errorf("GetFlight of a flight that does not exist returned %v, want a 404", err)
+		}
+	})
+}
+
+func TestClientBasicAuth(t *testing.T) {
+	forEachFormat(t, func(t *testing.T, s *tripittest.Server, c *tripit.Client) {
+		ctx := context.Background()
+
+		if _, err := c.GetCurrentProfile(ctx); err != nil {
+			t.Fatalf("the server's credentials were rejected: %v", err)
+		}
+
+		// The server only takes other credentials than the client's.
+		username, password := s.Username, s.Password
+		for _, creds := range [][2]string{
+			{username, "other password"},
+			{"someone@example.com", password},
+		} {
+			s.Username, s.Password = creds[0], creds[1]
+			_, err := c.GetCurrentProfile(ctx)
+			if err == nil || !strings.Contains(err.Error(), "status code 401") {
+				t.Errorf("server credentials %q returned %v, want a 401", creds[0], err)
+				continue
+			}
+			if strings.Contains(err.Error(), password) {
+				t.Errorf("the error has the password: %v", err)
+			}
+		}
+
+		s.Username, s.Password = username, password
+		if _, err := c.GetCurrentProfile(ctx); err != nil {
+			t.Errorf("the credentials were rejected after they were restored: %v", err)
+		}
+	})
+}
+
+func TestClientWrites(t *testing.T) {
+	forEachFormat(t, func(t *testing.T, s *tripittest.Server, c *tripit.Client) {
+		ctx := context.Background()
+
+		trip, err := c.CreateTrip(ctx, tripit.Trip{DisplayName: "New York", StartDate: "2999-01-01", EndDate: "2999-01-02", PrimaryLocation: "New York, NY"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if trip.ID == "" {
+			t.Fatal("created trip has no ID")
+		}
+
+		segment := tripit.FlightSegment{
+			StartDateTime:    tripit.DateTime{Date: "2999-01-01", Time: "09:00:00", Timezone: "America/Los_Angeles"},
+			EndDateTime:      tripit.DateTime{Date: "2999-01-01", Time: "17:30:00", Timezone: "America/New_York"},
+			StartAirportCode: "SFO",
+			EndAirportCode:   "JFK",
+		}
+		flight, err := c.CreateFlight(ctx, tripit.Flight{TripID: trip.ID, Segments: tripit.FlightSegments{segment}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if flight.ID == "" || len(flight.Segments) != 1 || flight.Segments[0].ID == "" {
+			t.Fatalf("created flight %+v has no IDs", flight)
+		}
+		if got := flight.Segments[0].StartDateTime; got != segment.StartDateTime {
+			t.Errorf("created flight starts %+v, want %+v", got, segment.StartDateTime)
+		}
+
+		flight.Segments[0].EndAirportCode = "EWR"
+		if _, err := c.ReplaceFlight(ctx, flight.ID, flight); err != nil {
+			t.Fatal(err)
+		}
+		if flights := s.Flights(); len(flights) != 1 || flights[0].Segments[0].EndAirportCode != "EWR" {
+			t.Errorf("flights after the replace are %+v, want one to EWR", flights)
+		}
+
+		id, err := strconv.ParseUint(trip.ID, 10, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.ShareTrip(ctx, tripit.TripShare{TripID: uint(id), IsTraveler: true}, "Join me", "friend@example.com"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := c.DeleteTrip(ctx, trip.ID); err != nil {
+			t.Fatal(err)
+		}
+		if trips, flights := s.Trips(), s.Flights(); len(trips) != 0 || len(flights) != 0 {
+			t.Errorf("got %d trips and %d flights after the delete, want none", len(trips), len(flights))
+		}
+	})
+}
+
+func TestClientContext(t *testing.T) {
+	forEachFormat(t, func(t *testing.T, s *tripittest.Server, c *tripit.Client) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := c.GetCurrentProfile(ctx); err == nil {
+			t.Error("GetCurrentProfile with a canceled context did not fail")
+		}
+		if err := c.TripIterator(true).ForEachPage(ctx, func(*tripit.Response) error { return nil }); err == nil {
+			t.Error("ForEachPage with a canceled context did not fail")
+		}
+		if n := len(s.Requests()); n != 0 {
+			t.Errorf("the server got %d requests with a canceled context, want none", n)
+		}
+	})
+}
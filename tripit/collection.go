@@ -0,0 +1,56 @@
+package tripit
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// The TripIt JSON API returns a single object instead of an array when a
+// collection only has one element. These functions are shared by all the
+// collection types so they decode either form and encode the same way, their
+// methods are generated for the slice types in types.go.
+
+//go:generate go run gen_collections.go
+
+// unmarshalOneOrMany decodes the JSON in b into the slice pointed to by v.
+// b may be null, a single object, or an array of objects.
+func unmarshalOneOrMany(b []byte, v interface{}) error {
+	s := reflect.ValueOf(v).Elem()
+
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || bytes.Equal(b, []byte("null")) {
+		s.Set(reflect.Zero(s.Type()))
+		return nil
+	}
+
+	if b[0] == '[' {
+		arr := reflect.New(reflect.SliceOf(s.Type().Elem()))
+		if err := json.Unmarshal(b, arr.Interface()); err != nil {
+			return err
+		}
+		s.Set(arr.Elem().Convert(s.Type()))
+		return nil
+	}
+
+	elem := reflect.New(s.Type().Elem())
+	if err := json.Unmarshal(b, elem.Interface()); err != nil {
+		return err
+	}
+	s.Set(reflect.Append(reflect.MakeSlice(s.Type(), 0, 1), elem.Elem()))
+	return nil
+}
+
+// marshalOneOrMany encodes the slice v as a single object if it has one
+// element, as null if it is empty, and as an array otherwise.
+func marshalOneOrMany(v interface{}) ([]byte, error) {
+	s := reflect.ValueOf(v)
+	switch s.Len() {
+	case 0:
+		return []byte("null"), nil
+	case 1:
+		return json.Marshal(s.Index(0).Interface())
+	}
+
+	return json.Marshal(s.Convert(reflect.SliceOf(s.Type().Elem())).Interface())
+}
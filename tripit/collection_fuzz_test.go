@@ -0,0 +1,49 @@
+//go:build go1.18
+// +build go1.18
+
+package tripit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func FuzzOneOrMany(f *testing.F) {
+	for _, seed := range []string{
+		`null`,
+		`{}`,
+		`[]`,
+		`{"id": "1", "Segment": {"id": "2", "Traveler": {"first_name": "Jess"}}}`,
+		`[{"id": "1", "Segment": [{"id": "2"}, {"id": "3", "start_airport_latitude": "37.6"}]}, {"id": "4"}]`,
+		`{"id": "1", "Traveler": [{"first_name": "Jess"}, {"first_name": "Ada"}]}`,
+		`[{"id": "1"}`,
+		`"1"`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var flights Flights
+		if err := flights.UnmarshalJSON(b); err != nil {
+			return
+		}
+
+		// What decodes encodes, and encodes the same after another round.
+		first, err := json.Marshal(flights)
+		if err != nil {
+			t.Fatalf("marshaling %+v decoded from %q failed: %v", flights, b, err)
+		}
+		var again Flights
+		if err := json.Unmarshal(first, &again); err != nil {
+			t.Fatalf("unmarshaling %s failed: %v", first, err)
+		}
+		second, err := json.Marshal(again)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(first, second) {
+			t.Errorf("round trip of %q changed\nfirst:  %s\nsecond: %s", b, first, second)
+		}
+	})
+}
@@ -0,0 +1,243 @@
+// Code generated by gen_collections.go; DO NOT EDIT.
+
+package tripit
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Errors) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Error)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Errors) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Error(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Warnings) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Warning)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Warnings) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Warning(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Activities) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Activity)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Activities) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Activity(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Cars) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Car)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Cars) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Car(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Cruises) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Cruise)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Cruises) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Cruise(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Directions) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Direction)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Directions) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Direction(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Flights) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Flight)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Flights) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Flight(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *FlightSegments) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]FlightSegment)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p FlightSegments) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]FlightSegment(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Lodges) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Lodging)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Lodges) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Lodging(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Maps) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Map)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Maps) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Map(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Notes) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Note)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Notes) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Note(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Rails) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Rail)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Rails) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Rail(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *RailSegments) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]RailSegment)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p RailSegments) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]RailSegment(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Restaurants) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Restaurant)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Restaurants) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Restaurant(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Transports) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Transport)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Transports) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Transport(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *TransportSegments) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]TransportSegment)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p TransportSegments) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]TransportSegment(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Trips) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Trip)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Trips) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Trip(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *WeatherReports) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Weather)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p WeatherReports) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Weather(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *PointsPrograms) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]PointsProgram)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p PointsPrograms) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]PointsProgram(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Profiles) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Profile)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Profiles) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Profile(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *ProfileEmailAddresses) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]ProfileEmailAddress)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p ProfileEmailAddresses) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]ProfileEmailAddress(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *GroupMemberships) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Group)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p GroupMemberships) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Group(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Travelers) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Traveler)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Travelers) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Traveler(p))
+}
+
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *Invitees) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]Invitee)(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p Invitees) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]Invitee(p))
+}
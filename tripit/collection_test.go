@@ -0,0 +1,130 @@
+package tripit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalOneOrMany(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    Travelers
+		wantErr bool
+	}{
+		{name: "null", in: `null`, want: nil},
+		{name: "null with space", in: " null\n", want: nil},
+		{name: "single", in: `{"first_name": "Jess"}`, want: Travelers{{FirstName: "Jess"}}},
+		{name: "empty object", in: `{}`, want: Travelers{{}}},
+		{name: "array", in: `[{"first_name": "Jess"}, {"first_name": "Ada"}]`, want: Travelers{{FirstName: "Jess"}, {FirstName: "Ada"}}},
+		{name: "array of one", in: `[{"first_name": "Jess"}]`, want: Travelers{{FirstName: "Jess"}}},
+		{name: "empty array", in: `[]`, want: Travelers{}},
+		{name: "unterminated object", in: `{"first_name": "Jess"`, wantErr: true},
+		{name: "unterminated array", in: `[{"first_name": "Jess"}`, wantErr: true},
+		{name: "string", in: `"Jess"`, wantErr: true},
+		{name: "number", in: `42`, wantErr: true},
+		{name: "array of strings", in: `["Jess"]`, wantErr: true},
+		{name: "wrong field type", in: `{"first_name": 42}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Decode it into a field like the responses do, json.Unmarshal
+			// does not call UnmarshalJSON for a top-level null.
+			var got struct {
+				Travelers Travelers `json:"Traveler"`
+			}
+			err := json.Unmarshal([]byte(`{"Traveler": `+tt.in+`}`), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", got.Travelers)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got.Travelers, tt.want) {
+				t.Errorf("got %#v, want %#v", got.Travelers, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalOneOrManyReplaces(t *testing.T) {
+	got := Travelers{{FirstName: "Old"}, {FirstName: "Older"}}
+	if err := json.Unmarshal([]byte(`{"first_name": "Jess"}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Travelers{{FirstName: "Jess"}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if err := (&got).UnmarshalJSON([]byte(`null`)); err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("got %+v after null, want nil", got)
+	}
+}
+
+func TestMarshalOneOrMany(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Travelers
+		want string
+	}{
+		{name: "nil", in: nil, want: `{"Traveler":null}`},
+		{name: "empty", in: Travelers{}, want: `{"Traveler":null}`},
+		{name: "single", in: Travelers{{FirstName: "Jess"}}, want: `{"Traveler":{"first_name":"Jess"}}`},
+		{name: "many", in: Travelers{{FirstName: "Jess"}, {FirstName: "Ada"}}, want: `{"Traveler":[{"first_name":"Jess"},{"first_name":"Ada"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(struct {
+				Travelers Travelers `json:"Traveler"`
+			}{tt.in})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOneOrManyNested(t *testing.T) {
+	// A flight with a single segment and a single traveler, and one with
+	// many of both, the way the API sends them.
+	const body = `{"AirObject": [
+		{"id": "1", "Segment": {"id": "2"}, "Traveler": {"first_name": "Jess"}},
+		{"id": "3", "Segment": [{"id": "4"}, {"id": "5"}], "Traveler": [{"first_name": "Jess"}, {"first_name": "Ada"}]}
+	]}`
+
+	var resp Response
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatal(err)
+	}
+	want := Flights{
+		{ID: "1", Segments: FlightSegments{{ID: "2"}}, Travelers: Travelers{{FirstName: "Jess"}}},
+		{ID: "3", Segments: FlightSegments{{ID: "4"}, {ID: "5"}}, Travelers: Travelers{{FirstName: "Jess"}, {FirstName: "Ada"}}},
+	}
+	if !reflect.DeepEqual(resp.Flights, want) {
+		t.Errorf("got %+v, want %+v", resp.Flights, want)
+	}
+
+	b, err := json.Marshal(resp.Flights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again Flights
+	if err := json.Unmarshal(b, &again); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, want) {
+		t.Errorf("round trip of %s got %+v, want %+v", b, again, want)
+	}
+}
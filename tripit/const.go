@@ -18,6 +18,9 @@ const (
 	// ListPointsProgramsEndpoint is the API endoint to list points programs.
 	ListPointsProgramsEndpoint = "/list/points_program"
 
+	// CreateEndpoint is the API endpoint to create objects.
+	CreateEndpoint = "/create"
+
 	// EndpointFormatGetObject is the endpoint format to get an object.
 	EndpointFormatGetObject = "get/%s/id/%s/%s"
 	// EndpointFormatDeleteObject is the endpoint format to delete an object.
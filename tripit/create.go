@@ -1,10 +1,402 @@
 package tripit
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 )
 
 // Create takes a Request object and creates it.
-func (c *Client) Create(req Request) (*Response, error) {
-	return c.doRequest(http.MethodPost, "v1/create", req)
+func (c *Client) Create(ctx context.Context, req Request) (*Response, error) {
+	return c.doRequest(ctx, http.MethodPost, CreateEndpoint, req)
+}
+
+// CreateActivity creates the activity and returns it with its server-assigned ID.
+func (c *Client) CreateActivity(ctx context.Context, activity Activity) (Activity, error) {
+	if err := activity.validate(); err != nil {
+		return Activity{}, err
+	}
+
+	resp, err := c.Create(ctx, Request{Activity: &activity})
+	if err != nil {
+		return Activity{}, err
+	}
+
+	// Check if we didn't get a result and return an error if true.
+	if len(resp.Activities) <= 0 {
+		return Activity{}, errors.New("create activity returned an empty result")
+	}
+
+	// Return the object.
+	return resp.Activities[0], nil
+}
+
+// CreateCar creates the car and returns it with its server-assigned ID.
+func (c *Client) CreateCar(ctx context.Context, car Car) (Car, error) {
+	if err := car.validate(); err != nil {
+		return Car{}, err
+	}
+
+	resp, err := c.Create(ctx, Request{Car: &car})
+	if err != nil {
+		return Car{}, err
+	}
+
+	// Check if we didn't get a result and return an error if true.
+	if len(resp.Cars) <= 0 {
+		return Car{}, errors.New("create car returned an empty result")
+	}
+
+	// Return the object.
+	return resp.Cars[0], nil
+}
+
+// CreateCruise creates the cruise and returns it with its server-assigned ID.
+func (c *Client) CreateCruise(ctx context.Context, cruise Cruise) (Cruise, error) {
+	if err := cruise.validate(); err != nil {
+		return Cruise{}, err
+	}
+
+	resp, err := c.Create(ctx, Request{Cruise: &cruise})
+	if err != nil {
+		return Cruise{}, err
+	}
+
+	// Check if we didn't get a result and return an error if true.
+	if len(resp.Cruises) <= 0 {
+		return Cruise{}, errors.New("create cruise returned an empty result")
+	}
+
+	// Return the object.
+	return resp.Cruises[0], nil
+}
+
+// CreateDirections creates the directions and returns them with their server-assigned ID.
+func (c *Client) CreateDirections(ctx context.Context, directions Direction) (Direction, error) {
+	if err := directions.validate(); err != nil {
+		return Direction{}, err
+	}
+
+	resp, err := c.Create(ctx, Request{Directions: &directions})
+	if err != nil {
+		return Direction{}, err
+	}
+
+	// Check if we didn't get a result and return an error if true.
+	if len(resp.Directions) <= 0 {
+		return Direction{}, errors.New("create directions returned an empty result")
+	}
+
+	// Return the object.
+	return resp.Directions[0], nil
+}
+
+// CreateFlight creates the flight and returns it with its server-assigned ID.
+func (c *Client) CreateFlight(ctx context.Context, flight Flight) (Flight, error) {
+	if err := flight.validate(); err != nil {
+		return Flight{}, err
+	}
+
+	resp, err := c.Create(ctx, Request{Flight: &flight})
+	if err != nil {
+		return Flight{}, err
+	}
+
+	// Check if we didn't get a result and return an error if true.
+	if len(resp.Flights) <= 0 {
+		return Flight{}, errors.New("create flight returned an empty result")
+	}
+
+	// Return the object.
+	return resp.Flights[0], nil
+}
+
+// CreateLodging creates the lodging and returns it with its server-assigned ID.
+func (c *Client) CreateLodging(ctx context.Context, lodging Lodging) (Lodging, error) {
+	if err := lodging.validate(); err != nil {
+		return Lodging{}, err
+	}
+
+	resp, err := c.Create(ctx, Request{Lodging: &lodging})
+	if err != nil {
+		return Lodging{}, err
+	}
+
+	// Check if we didn't get a result and return an error if true.
+	if len(resp.Lodging) <= 0 {
+		return Lodging{}, errors.New("create lodging returned an empty result")
+	}
+
+	// Return the object.
+	return resp.Lodging[0], nil
+}
+
+// CreateMap creates the map and returns it with its server-assigned ID.
+func (c *Client) CreateMap(ctx context.Context, m Map) (Map, error) {
+	if err := m.validate(); err != nil {
+		return Map{}, err
+	}
+
+	resp, err := c.Create(ctx, Request{Map: &m})
+	if err != nil {
+		return Map{}, err
+	}
+
+	// Check if we didn't get a result and return an error if true.
+	if len(resp.Maps) <= 0 {
+		return Map{}, errors.New("create map returned an empty result")
+	}
+
+	// Return the object.
+	return resp.Maps[0], nil
+}
+
+// CreateNote creates the note and returns it with its server-assigned ID.
+func (c *Client) CreateNote(ctx context.Context, note Note) (Note, error) {
+	if err := note.validate(); err != nil {
+		return Note{}, err
+	}
+
+	resp, err := c.Create(ctx, Request{Note: &note})
+	if err != nil {
+		return Note{}, err
+	}
+
+	// Check if we didn't get a result and return an error if true.
+	if len(resp.Notes) <= 0 {
+		return Note{}, errors.New("create note returned an empty result")
+	}
+
+	// Return the object.
+	return resp.Notes[0], nil
+}
+
+// CreateRail creates the rail and returns it with its server-assigned ID.
+func (c *Client) CreateRail(ctx context.Context, rail Rail) (Rail, error) {
+	if err := rail.validate(); err != nil {
+		return Rail{}, err
+	}
+
+	resp, err := c.Create(ctx, Request{Rail: &rail})
+	if err != nil {
+		return Rail{}, err
+	}
+
+	// Check if we didn't get a result and return an error if true.
+	if len(resp.Rails) <= 0 {
+		return Rail{}, errors.New("create rail returned an empty result")
+	}
+
+	// Return the object.
+	return resp.Rails[0], nil
+}
+
+// CreateRestaurant creates the restaurant and returns it with its server-assigned ID.
+func (c *Client) CreateRestaurant(ctx context.Context, restaurant Restaurant) (Restaurant, error) {
+	if err := restaurant.validate(); err != nil {
+		return Restaurant{}, err
+	}
+
+	resp, err := c.Create(ctx, Request{Restaurant: &restaurant})
+	if err != nil {
+		return Restaurant{}, err
+	}
+
+	// Check if we didn't get a result and return an error if true.
+	if len(resp.Restaurants) <= 0 {
+		return Restaurant{}, errors.New("create restaurant returned an empty result")
+	}
+
+	// Return the object.
+	return resp.Restaurants[0], nil
+}
+
+// CreateTransport creates the transport and returns it with its server-assigned ID.
+func (c *Client) CreateTransport(ctx context.Context, transport Transport) (Transport, error) {
+	if err := transport.validate(); err != nil {
+		return Transport{}, err
+	}
+
+	resp, err := c.Create(ctx, Request{Transport: &transport})
+	if err != nil {
+		return Transport{}, err
+	}
+
+	// Check if we didn't get a result and return an error if true.
+	if len(resp.Transports) <= 0 {
+		return Transport{}, errors.New("create transport returned an empty result")
+	}
+
+	// Return the object.
+	return resp.Transports[0], nil
+}
+
+// CreateTrip creates the trip and returns it with its server-assigned ID.
+func (c *Client) CreateTrip(ctx context.Context, trip Trip) (Trip, error) {
+	if err := trip.validate(); err != nil {
+		return Trip{}, err
+	}
+
+	resp, err := c.Create(ctx, Request{Trip: &trip})
+	if err != nil {
+		return Trip{}, err
+	}
+
+	// Check if we didn't get a result and return an error if true.
+	if len(resp.Trips) <= 0 {
+		return Trip{}, errors.New("create trip returned an empty result")
+	}
+
+	// Return the object.
+	return resp.Trips[0], nil
+}
+
+func (a Activity) validate() error {
+	if a.TripID == "" {
+		return errors.New("activity trip_id cannot be empty")
+	}
+	if a.StartDateTime.Date == "" {
+		return errors.New("activity StartDateTime cannot be empty")
+	}
+	return nil
+}
+
+func (c Car) validate() error {
+	if c.TripID == "" {
+		return errors.New("car trip_id cannot be empty")
+	}
+	if c.StartDateTime.Date == "" {
+		return errors.New("car StartDateTime cannot be empty")
+	}
+	if c.EndDateTime.Date == "" {
+		return errors.New("car EndDateTime cannot be empty")
+	}
+	return nil
+}
+
+func (c Cruise) validate() error {
+	if c.TripID == "" {
+		return errors.New("cruise trip_id cannot be empty")
+	}
+	if len(c.Segments) < 1 {
+		return errors.New("cruise must have at least one segment")
+	}
+	for i, segment := range c.Segments {
+		if segment.StartDateTime.Date == "" {
+			return fmt.Errorf("cruise segment %d StartDateTime cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+func (d Direction) validate() error {
+	if d.TripID == "" {
+		return errors.New("directions trip_id cannot be empty")
+	}
+	if d.DateTime.Date == "" {
+		return errors.New("directions DateTime cannot be empty")
+	}
+	return nil
+}
+
+func (f Flight) validate() error {
+	if f.TripID == "" {
+		return errors.New("flight trip_id cannot be empty")
+	}
+	if len(f.Segments) < 1 {
+		return errors.New("flight must have at least one segment")
+	}
+	for i, segment := range f.Segments {
+		if segment.StartDateTime.Date == "" {
+			return fmt.Errorf("flight segment %d StartDateTime cannot be empty", i)
+		}
+		if segment.EndDateTime.Date == "" {
+			return fmt.Errorf("flight segment %d EndDateTime cannot be empty", i)
+		}
+		if segment.StartAirportCode == "" || segment.EndAirportCode == "" {
+			return fmt.Errorf("flight segment %d airport codes cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+func (l Lodging) validate() error {
+	if l.TripID == "" {
+		return errors.New("lodging trip_id cannot be empty")
+	}
+	if l.StartDateTime.Date == "" {
+		return errors.New("lodging StartDateTime cannot be empty")
+	}
+	if l.EndDateTime.Date == "" {
+		return errors.New("lodging EndDateTime cannot be empty")
+	}
+	return nil
+}
+
+func (m Map) validate() error {
+	if m.TripID == "" {
+		return errors.New("map trip_id cannot be empty")
+	}
+	return nil
+}
+
+func (n Note) validate() error {
+	if n.TripID == "" {
+		return errors.New("note trip_id cannot be empty")
+	}
+	return nil
+}
+
+func (r Rail) validate() error {
+	if r.TripID == "" {
+		return errors.New("rail trip_id cannot be empty")
+	}
+	if len(r.Segments) < 1 {
+		return errors.New("rail must have at least one segment")
+	}
+	for i, segment := range r.Segments {
+		if segment.StartDateTime.Date == "" {
+			return fmt.Errorf("rail segment %d StartDateTime cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+func (r Restaurant) validate() error {
+	if r.TripID == "" {
+		return errors.New("restaurant trip_id cannot be empty")
+	}
+	if r.DateTime.Date == "" {
+		return errors.New("restaurant DateTime cannot be empty")
+	}
+	return nil
+}
+
+func (t Transport) validate() error {
+	if t.TripID == "" {
+		return errors.New("transport trip_id cannot be empty")
+	}
+	if len(t.Segments) < 1 {
+		return errors.New("transport must have at least one segment")
+	}
+	for i, segment := range t.Segments {
+		if segment.StartDateTime.Date == "" {
+			return fmt.Errorf("transport segment %d StartDateTime cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+func (t Trip) validate() error {
+	if t.StartDate == "" {
+		return errors.New("trip start_date cannot be empty")
+	}
+	if t.EndDate == "" {
+		return errors.New("trip end_date cannot be empty")
+	}
+	if t.PrimaryLocation == "" {
+		return errors.New("trip primary_location cannot be empty")
+	}
+	return nil
 }
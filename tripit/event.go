@@ -8,77 +8,229 @@ import (
 	calendar "google.golang.org/api/calendar/v3"
 )
 
+// DescriptionField is a part of the description of an event.
+type DescriptionField string
+
 const (
-	eventDescriptionFormat = `[Flight] %s to %s
-%s
+	// FieldRoute is the airports and the departure time, or the place and
+	// the times of the events that are not flights.
+	FieldRoute DescriptionField = "route"
+	// FieldConfirmation is the confirmation numbers and the record locator.
+	FieldConfirmation DescriptionField = "confirmation"
+	// FieldAirline is the airline and the flight number.
+	FieldAirline DescriptionField = "airline"
+	// FieldDeparture is the departure terminal and gate.
+	FieldDeparture DescriptionField = "departure"
+	// FieldArrival is the arrival airport and time.
+	FieldArrival DescriptionField = "arrival"
+	// FieldDuration is the duration of the flight.
+	FieldDuration DescriptionField = "duration"
+	// FieldDistance is the distance of the flight.
+	FieldDistance DescriptionField = "distance"
+	// FieldCheckInURL is the check-in URL of the airline.
+	FieldCheckInURL DescriptionField = "check_in_url"
+	// FieldLinks is the links to the flight and the trip in TripIt.
+	FieldLinks DescriptionField = "links"
+)
 
-Booking Site (%s) Confirmation # %s
-Supplier (%s) Confirmation # %s
-Record Locator # %s
+// DescriptionFields are all the parts of the description, in order.
+var DescriptionFields = []DescriptionField{
+	FieldRoute,
+	FieldConfirmation,
+	FieldAirline,
+	FieldDeparture,
+	FieldArrival,
+	FieldDuration,
+	FieldDistance,
+	FieldCheckInURL,
+	FieldLinks,
+}
 
-Airline: %s %s
+const (
+	routeFormat = `[Flight] %s to %s
+%s`
+	confirmationFormat = `Booking Site (%s) Confirmation # %s
+Supplier (%s) Confirmation # %s
+Record Locator # %s`
+	airlineFormat   = `Airline: %s %s`
+	departureFormat = `Departing Terminal %s Gate %s`
+	arrivalFormat   = `Arrive -> %s (%s)
+%s`
+	durationFormat   = `Duration: %s`
+	distanceFormat   = `Distance: %s`
+	checkInURLFormat = `Check-in URL: %s`
+	linksFormat      = `View and/or edit details of this flight [%s]: https://www.tripit.com/%s
 
-Departing Terminal %s Gate %s
+View and/or edit details of this trip: https://www.tripit.com/trip/show/id/%s`
 
-Arrive -> %s (%s)
-%s
+	tripFormat = `[Trip] %s
+%s to %s`
+	tripLinksFormat = `View and/or edit details of this trip: https://www.tripit.com/trip/show/id/%s`
 
-Duration: %s
+	lodgingFormat = `[Lodging] %s
+Check-in %s
+Check-out %s`
+	carFormat = `[Car] %s
+Pick-up %s at %s
+Drop-off %s at %s`
+	railFormat = `[Rail] %s to %s
+%s`
+	activityFormat = `[Activity] %s
+%s`
+	objectConfirmationFormat = `Booking Site (%s) Confirmation # %s
+Supplier (%s) Confirmation # %s`
 
-Distance: %s
+	eventColorID    = "3"
+	bufferColorID   = "8"
+	tripColorID     = "7"
+	lodgingColorID  = "2"
+	carColorID      = "5"
+	railColorID     = "9"
+	activityColorID = "4"
+)
 
-Check-in URL: %s
+// EventKind is the kind of calendar event created for a TripIt object.
+type EventKind string
 
-View and/or edit details of this flight [%s]: https://www.tripit.com/%s
+const (
+	// EventKindFlight is the event for a flight segment.
+	EventKindFlight EventKind = "flight"
+	// EventKindBuffer is the event for the travel time to or from the airport.
+	EventKindBuffer EventKind = "buffer"
+	// EventKindTrip is the all day event for the days of a trip.
+	EventKindTrip EventKind = "trip"
+	// EventKindLodging is the event for a hotel stay, from check-in to
+	// check-out.
+	EventKindLodging EventKind = "lodging"
+	// EventKindCar is the event for a car rental, from pick-up to drop-off.
+	EventKindCar EventKind = "car"
+	// EventKindRail is the event for a rail segment.
+	EventKindRail EventKind = "rail"
+	// EventKindActivity is the event for an activity.
+	EventKindActivity EventKind = "activity"
+)
 
-View and/or edit details of this trip: https://www.tripit.com/trip/show/id/%s`
+// EventKinds are all the kinds of events.
+var EventKinds = []EventKind{
+	EventKindFlight,
+	EventKindBuffer,
+	EventKindTrip,
+	EventKindLodging,
+	EventKindCar,
+	EventKindRail,
+	EventKindActivity,
+}
 
-	eventColorID  = "3"
-	bufferColorID = "8"
-)
+// ObjectType returns the TripIt object type the events of the kind are
+// created for.
+func (k EventKind) ObjectType() Type {
+	switch k {
+	case EventKindFlight, EventKindBuffer:
+		return TypeFlight
+	case EventKindTrip:
+		return TypeTrip
+	case EventKindLodging:
+		return TypeLodging
+	case EventKindCar:
+		return TypeCar
+	case EventKindRail:
+		return TypeRail
+	case EventKindActivity:
+		return TypeActivity
+	}
+	return ""
+}
 
 // Event holds the data we will use when creating calendar events for flights, activities, and other
 // TripIt API objects.
 type Event struct {
-	Title              string
-	Description        string
-	AirportCode        string
-	Start              calendar.EventDateTime
-	End                calendar.EventDateTime
-	ID                 string
-	SegmentID          string
+	Kind        EventKind
+	Title       string
+	Description string
+	AirportCode string
+	// Location is the address of the events that are not at an airport.
+	Location  string
+	Start     calendar.EventDateTime
+	End       calendar.EventDateTime
+	ID        string
+	SegmentID string
+	// Key identifies the event of the segment, since a segment has up to
+	// three events.
+	Key                string
 	ConfirmationNumber string
-	ColorID            string
+	CheckInURL         string
+	// StartAirportCode and EndAirportCode are the airports of the flight.
+	StartAirportCode string
+	EndAirportCode   string
+	// AirlineCode is the code of the airline in the title, the operating
+	// airline if there is one.
+	AirlineCode string
+	// Status is the flight status TripIt is monitoring, if any.
+	Status  FlightStatusCode
+	ColorID string
+	// SharedBy is the name of the traveler if the user is not traveling themselves.
+	SharedBy string
+	// Travelers are the full names of the travelers on the flight.
+	Travelers []string
+	// TravelerRefs are the profile refs of the invitees of the trip that
+	// are traveling, TripIt only has them on the trip.
+	TravelerRefs []string
+
+	// sections are the parts of the description.
+	sections []descriptionSection
+}
+
+type descriptionSection struct {
+	field DescriptionField
+	text  string
+}
+
+// DescriptionOf returns the description of the event with only the fields
+// given.
+func (e Event) DescriptionOf(fields []DescriptionField) string {
+	var parts []string
+	for _, section := range e.sections {
+		for _, field := range fields {
+			if section.field == field {
+				parts = append(parts, section.text)
+				break
+			}
+		}
+	}
+	return strings.Join(parts, "\n\n")
 }
 
 // GetFlightSegmentsAsEvents returns an Event object for each of the
-// flight segments in the given flight object.
-func (f Flight) GetFlightSegmentsAsEvents() ([]Event, error) {
+// flight segments in the given flight object. The times are resolved
+// to their time zone with tz, which may be nil.
+func (f Flight) GetFlightSegmentsAsEvents(tz *TimezoneResolver) ([]Event, error) {
 	// Initialize our events array.
 	events := []Event{}
 
+	travelers := travelerNames(f.Travelers)
+
 	// Iterate over the flight segments.
 	for i := 0; i < len(f.Segments); i++ {
 		segment := f.Segments[i]
 
 		// Get the flight start time.
-		startDate, err := segment.StartDateTime.Parse()
+		startDate, startTimezone, err := tz.Parse(segment.StartDateTime, segment.StartAirportCode)
 		if err != nil {
 			return nil, fmt.Errorf("parsing StartDateTime for tripID -> %s, segment -> %s, from %s -> %s failed: %v", f.TripID, segment.ID, segment.StartAirportCode, segment.EndAirportCode, err)
 		}
 		start := calendar.EventDateTime{
 			DateTime: startDate.Format(time.RFC3339),
-			TimeZone: segment.StartDateTime.Timezone,
+			TimeZone: startTimezone,
 		}
 
 		// Get the flight end time.
-		endDate, err := segment.EndDateTime.Parse()
+		endDate, endTimezone, err := tz.Parse(segment.EndDateTime, segment.EndAirportCode)
 		if err != nil {
 			return nil, fmt.Errorf("parsing EndDateTime for tripID -> %s, segment -> %s, from %s -> %s failed: %v", f.TripID, segment.ID, segment.StartAirportCode, segment.EndAirportCode, err)
 		}
 		end := calendar.EventDateTime{
 			DateTime: endDate.Format(time.RFC3339),
-			TimeZone: segment.EndDateTime.Timezone,
+			TimeZone: endTimezone,
 		}
 
 		// Sort out operating versus marketing airline
@@ -94,28 +246,32 @@ func (f Flight) GetFlightSegmentsAsEvents() ([]Event, error) {
 		}
 
 		// Create a description for the flight segment.
-		description := fmt.Sprintf(eventDescriptionFormat,
-			segment.StartAirportCode,
-			segment.EndAirportCode,
-			startDate.Format(time.RFC1123Z),
-			f.BookingSiteName,
-			f.BookingSiteConfNum,
-			f.SupplierName,
-			f.SupplierConfNum,
-			f.RecordLocator,
-			airlineName,
-			flightNumber,
-			segment.StartTerminal,
-			segment.StartGate,
-			segment.EndCityName,
-			segment.EndAirportCode,
-			endDate.Format(time.RFC1123Z),
-			segment.Duration,
-			segment.Distance,
-			segment.CheckInURL,
-			segment.ID,
-			strings.TrimPrefix(f.RelativeURL, "/"),
-			f.TripID)
+		sections := []descriptionSection{
+			{FieldRoute, fmt.Sprintf(routeFormat,
+				segment.StartAirportCode,
+				segment.EndAirportCode,
+				startDate.Format(time.RFC1123Z))},
+			{FieldConfirmation, fmt.Sprintf(confirmationFormat,
+				f.BookingSiteName,
+				f.BookingSiteConfNum,
+				f.SupplierName,
+				f.SupplierConfNum,
+				f.RecordLocator)},
+			{FieldAirline, fmt.Sprintf(airlineFormat, airlineName, flightNumber)},
+			{FieldDeparture, fmt.Sprintf(departureFormat, segment.StartTerminal, segment.StartGate)},
+			{FieldArrival, fmt.Sprintf(arrivalFormat,
+				segment.EndCityName,
+				segment.EndAirportCode,
+				endDate.Format(time.RFC1123Z))},
+			{FieldDuration, fmt.Sprintf(durationFormat, segment.Duration)},
+			{FieldDistance, fmt.Sprintf(distanceFormat, segment.Distance)},
+			{FieldCheckInURL, fmt.Sprintf(checkInURLFormat, segment.CheckInURL)},
+			{FieldLinks, fmt.Sprintf(linksFormat,
+				segment.ID,
+				strings.TrimPrefix(f.RelativeURL, "/"),
+				f.TripID)},
+		}
+		description := Event{sections: sections}.DescriptionOf(DescriptionFields)
 
 		var confirmationNumber string
 		if f.SupplierConfNum != "" {
@@ -126,6 +282,7 @@ func (f Flight) GetFlightSegmentsAsEvents() ([]Event, error) {
 
 		// Append the event to our events array.
 		events = append(events, Event{
+			Kind:               EventKindFlight,
 			Title:              fmt.Sprintf("Flight to %s (%s %s)", segment.EndCityName, airlineCode, flightNumber),
 			Description:        description,
 			AirportCode:        segment.StartAirportCode,
@@ -133,54 +290,387 @@ func (f Flight) GetFlightSegmentsAsEvents() ([]Event, error) {
 			End:                end,
 			ID:                 f.TripID,
 			SegmentID:          segment.ID,
+			Key:                segment.ID,
 			ConfirmationNumber: confirmationNumber,
+			CheckInURL:         segment.CheckInURL,
+			StartAirportCode:   segment.StartAirportCode,
+			EndAirportCode:     segment.EndAirportCode,
+			AirlineCode:        airlineCode,
+			Status:             segment.Status.FlightStatus,
 			ColorID:            eventColorID,
+			Travelers:          travelers,
+			sections:           sections,
 		})
 
 		// If we have the first item in the segment, create the buffer event
 		// for travel time to the airport.
 		if i == 0 {
 			events = append(events, Event{
+				Kind:        EventKindBuffer,
 				Title:       fmt.Sprintf("Buffer for travel time to %s & security", segment.StartAirportCode),
 				Description: description,
 				AirportCode: segment.StartAirportCode,
 				Start: calendar.EventDateTime{
 					DateTime: startDate.Add(-3 * time.Hour).Format(time.RFC3339),
-					TimeZone: segment.StartDateTime.Timezone,
+					TimeZone: startTimezone,
 				},
 				End: calendar.EventDateTime{
 					DateTime: startDate.Format(time.RFC3339),
-					TimeZone: segment.StartDateTime.Timezone,
+					TimeZone: startTimezone,
 				},
 				ID:                 f.TripID,
 				SegmentID:          segment.ID,
+				Key:                segment.ID + "-to",
 				ConfirmationNumber: confirmationNumber,
+				CheckInURL:         segment.CheckInURL,
+				StartAirportCode:   segment.StartAirportCode,
+				EndAirportCode:     segment.EndAirportCode,
+				AirlineCode:        airlineCode,
+				Status:             segment.Status.FlightStatus,
 				ColorID:            bufferColorID,
+				Travelers:          travelers,
+				sections:           sections,
 			})
 		}
 		// If we have the last item in the segment, create the buffer event
 		// for travel time from the airport.
 		if i == len(f.Segments)-1 {
 			events = append(events, Event{
+				Kind:        EventKindBuffer,
 				Title:       fmt.Sprintf("Buffer for travel time from %s", segment.EndAirportCode),
 				Description: description,
 				// TODO: put the hotel as location here.
 				AirportCode: "",
 				Start: calendar.EventDateTime{
 					DateTime: endDate.Format(time.RFC3339),
-					TimeZone: segment.EndDateTime.Timezone,
+					TimeZone: endTimezone,
 				},
 				End: calendar.EventDateTime{
 					DateTime: endDate.Add(2 * time.Hour).Format(time.RFC3339),
-					TimeZone: segment.EndDateTime.Timezone,
+					TimeZone: endTimezone,
 				},
 				ID:                 f.TripID,
 				SegmentID:          segment.ID,
+				Key:                segment.ID + "-from",
 				ConfirmationNumber: confirmationNumber,
+				CheckInURL:         segment.CheckInURL,
+				StartAirportCode:   segment.StartAirportCode,
+				EndAirportCode:     segment.EndAirportCode,
+				AirlineCode:        airlineCode,
+				Status:             segment.Status.FlightStatus,
 				ColorID:            bufferColorID,
+				Travelers:          travelers,
+				sections:           sections,
 			})
 		}
 	}
 
 	return events, nil
 }
+
+// EventKeys returns the keys of the events of the flight without making
+// them, so they are known even if the flight cannot be turned into events.
+func (f Flight) EventKeys() []string {
+	var keys []string
+	for i, segment := range f.Segments {
+		keys = append(keys, segment.ID)
+		if i == 0 {
+			keys = append(keys, segment.ID+"-to")
+		}
+		if i == len(f.Segments)-1 {
+			keys = append(keys, segment.ID+"-from")
+		}
+	}
+	return keys
+}
+
+// EventKey returns the key of the all day Event of the trip.
+func (t Trip) EventKey() string {
+	return "trip-" + t.ID
+}
+
+// GetTripAsEvent returns the all day Event for the days of the trip, a
+// banner over the other events of the trip. The title says the traveler is
+// traveling, for calendars shared with others.
+func (t Trip) GetTripAsEvent(traveler string) (Event, error) {
+	start, err := time.Parse("2006-01-02", t.StartDate)
+	if err != nil {
+		return Event{}, fmt.Errorf("parsing StartDate for tripID -> %s failed: %v", t.ID, err)
+	}
+	end, err := time.Parse("2006-01-02", t.EndDate)
+	if err != nil {
+		return Event{}, fmt.Errorf("parsing EndDate for tripID -> %s failed: %v", t.ID, err)
+	}
+
+	destination := t.PrimaryLocation
+	if destination == "" {
+		destination = t.DisplayName
+	}
+	title := fmt.Sprintf("Traveling to %s", destination)
+	if traveler != "" {
+		title = fmt.Sprintf("%s traveling to %s", traveler, destination)
+	}
+
+	sections := []descriptionSection{
+		{FieldRoute, fmt.Sprintf(tripFormat, t.DisplayName, start.Format("Mon, 02 Jan 2006"), end.Format("Mon, 02 Jan 2006"))},
+		{FieldLinks, fmt.Sprintf(tripLinksFormat, t.ID)},
+	}
+
+	return Event{
+		Kind:        EventKindTrip,
+		Title:       title,
+		Description: Event{sections: sections}.DescriptionOf(DescriptionFields),
+		Start: calendar.EventDateTime{
+			Date: start.Format("2006-01-02"),
+		},
+		// The end date of all day events is exclusive.
+		End: calendar.EventDateTime{
+			Date: end.AddDate(0, 0, 1).Format("2006-01-02"),
+		},
+		ID:       t.ID,
+		Key:      t.EventKey(),
+		ColorID:  tripColorID,
+		sections: sections,
+	}, nil
+}
+
+// EventKey returns the key of the event of the lodging.
+func (l Lodging) EventKey() string {
+	return "lodging-" + l.ID
+}
+
+// GetLodgingAsEvent returns the Event for the stay at the lodging, from
+// check-in to check-out. The times are resolved to their time zone with
+// tz, which may be nil.
+func (l Lodging) GetLodgingAsEvent(tz *TimezoneResolver) (Event, error) {
+	start, end, checkIn, checkOut, err := eventTimes(l.StartDateTime, l.EndDateTime, tz)
+	if err != nil {
+		return Event{}, fmt.Errorf("parsing the times of lodging %s for tripID -> %s failed: %v", l.ID, l.TripID, err)
+	}
+
+	name := firstNonEmpty(l.SupplierName, l.DisplayName, "Lodging")
+	sections := []descriptionSection{
+		{FieldRoute, fmt.Sprintf(lodgingFormat, name, checkIn, checkOut)},
+		{FieldConfirmation, fmt.Sprintf(objectConfirmationFormat, l.BookingSiteName, l.BookingSiteConfNum, l.SupplierName, l.SupplierConfNum)},
+		{FieldLinks, fmt.Sprintf(tripLinksFormat, l.TripID)},
+	}
+
+	return Event{
+		Kind:               EventKindLodging,
+		Title:              fmt.Sprintf("Stay at %s", name),
+		Description:        Event{sections: sections}.DescriptionOf(DescriptionFields),
+		Location:           formatAddress(l.Address),
+		Start:              start,
+		End:                end,
+		ID:                 l.TripID,
+		Key:                l.EventKey(),
+		ConfirmationNumber: firstNonEmpty(l.SupplierConfNum, l.BookingSiteConfNum),
+		ColorID:            lodgingColorID,
+		Travelers:          travelerNames(l.Guests),
+		sections:           sections,
+	}, nil
+}
+
+// EventKey returns the key of the event of the car rental.
+func (c Car) EventKey() string {
+	return "car-" + c.ID
+}
+
+// GetCarAsEvent returns the Event for the car rental, from pick-up to
+// drop-off. The times are resolved to their time zone with tz, which may
+// be nil.
+func (c Car) GetCarAsEvent(tz *TimezoneResolver) (Event, error) {
+	start, end, pickUp, dropOff, err := eventTimes(c.StartDateTime, c.EndDateTime, tz)
+	if err != nil {
+		return Event{}, fmt.Errorf("parsing the times of car %s for tripID -> %s failed: %v", c.ID, c.TripID, err)
+	}
+
+	name := firstNonEmpty(c.SupplierName, c.DisplayName, "Car rental")
+	startLocation := firstNonEmpty(c.StartLocationName, formatAddress(c.StartLocationAddress))
+	endLocation := firstNonEmpty(c.EndLocationName, formatAddress(c.EndLocationAddress))
+	sections := []descriptionSection{
+		{FieldRoute, fmt.Sprintf(carFormat, name, pickUp, startLocation, dropOff, endLocation)},
+		{FieldConfirmation, fmt.Sprintf(objectConfirmationFormat, c.BookingSiteName, c.BookingSiteConfNum, c.SupplierName, c.SupplierConfNum)},
+		{FieldLinks, fmt.Sprintf(tripLinksFormat, c.TripID)},
+	}
+
+	return Event{
+		Kind:               EventKindCar,
+		Title:              fmt.Sprintf("Car rental: %s", name),
+		Description:        Event{sections: sections}.DescriptionOf(DescriptionFields),
+		Location:           firstNonEmpty(formatAddress(c.StartLocationAddress), c.StartLocationName),
+		Start:              start,
+		End:                end,
+		ID:                 c.TripID,
+		Key:                c.EventKey(),
+		ConfirmationNumber: firstNonEmpty(c.SupplierConfNum, c.BookingSiteConfNum),
+		ColorID:            carColorID,
+		Travelers:          travelerNames(c.Drivers),
+		sections:           sections,
+	}, nil
+}
+
+// EventKeys returns the keys of the events of the rail segments without
+// making them, so they are known even if they cannot be turned into events.
+func (r Rail) EventKeys() []string {
+	var keys []string
+	for _, segment := range r.Segments {
+		keys = append(keys, "rail-"+segment.ID)
+	}
+	return keys
+}
+
+// GetRailSegmentsAsEvents returns an Event for each of the rail segments.
+// The times are resolved to their time zone with tz, which may be nil.
+func (r Rail) GetRailSegmentsAsEvents(tz *TimezoneResolver) ([]Event, error) {
+	events := []Event{}
+	travelers := travelerNames(r.Travelers)
+
+	for _, segment := range r.Segments {
+		start, end, departure, _, err := eventTimes(segment.StartDateTime, segment.EndDateTime, tz)
+		if err != nil {
+			return nil, fmt.Errorf("parsing the times of rail segment %s for tripID -> %s, from %s -> %s failed: %v", segment.ID, r.TripID, segment.StartStationName, segment.EndStationName, err)
+		}
+
+		sections := []descriptionSection{
+			{FieldRoute, fmt.Sprintf(railFormat, segment.StartStationName, segment.EndStationName, departure)},
+			{FieldConfirmation, fmt.Sprintf(objectConfirmationFormat, r.BookingSiteName, r.BookingSiteConfNum, r.SupplierName, r.SupplierConfNum)},
+			{FieldLinks, fmt.Sprintf(tripLinksFormat, r.TripID)},
+		}
+
+		title := fmt.Sprintf("Train to %s", segment.EndStationName)
+		if train := strings.TrimSpace(segment.CarrierName + " " + segment.TrainNumber); train != "" {
+			title = fmt.Sprintf("%s (%s)", title, train)
+		}
+
+		events = append(events, Event{
+			Kind:               EventKindRail,
+			Title:              title,
+			Description:        Event{sections: sections}.DescriptionOf(DescriptionFields),
+			Location:           firstNonEmpty(formatAddress(segment.StartStationAddress), segment.StartStationName),
+			Start:              start,
+			End:                end,
+			ID:                 r.TripID,
+			SegmentID:          segment.ID,
+			Key:                "rail-" + segment.ID,
+			ConfirmationNumber: firstNonEmpty(segment.ConfirmationNum, r.SupplierConfNum, r.BookingSiteConfNum),
+			ColorID:            railColorID,
+			Travelers:          travelers,
+			sections:           sections,
+		})
+	}
+
+	return events, nil
+}
+
+// EventKey returns the key of the event of the activity.
+func (a Activity) EventKey() string {
+	return "activity-" + a.ID
+}
+
+// GetActivityAsEvent returns the Event for the activity, which ends at its
+// end time on the day it starts. The times are resolved to their time zone
+// with tz, which may be nil.
+func (a Activity) GetActivityAsEvent(tz *TimezoneResolver) (Event, error) {
+	var endDateTime DateTime
+	if a.EndTime != "" {
+		endDateTime = a.StartDateTime
+		endDateTime.Time = a.EndTime
+	}
+	start, end, startTime, _, err := eventTimes(a.StartDateTime, endDateTime, tz)
+	if err != nil {
+		return Event{}, fmt.Errorf("parsing the times of activity %s for tripID -> %s failed: %v", a.ID, a.TripID, err)
+	}
+
+	name := firstNonEmpty(a.DisplayName, a.LocationName, "Activity")
+	sections := []descriptionSection{
+		{FieldRoute, fmt.Sprintf(activityFormat, name, startTime)},
+		{FieldConfirmation, fmt.Sprintf(objectConfirmationFormat, a.BookingSiteName, a.BookingSiteConfNum, a.SupplierName, a.SupplierConfNum)},
+		{FieldLinks, fmt.Sprintf(tripLinksFormat, a.TripID)},
+	}
+
+	return Event{
+		Kind:               EventKindActivity,
+		Title:              name,
+		Description:        Event{sections: sections}.DescriptionOf(DescriptionFields),
+		Location:           firstNonEmpty(formatAddress(a.Address), a.LocationName),
+		Start:              start,
+		End:                end,
+		ID:                 a.TripID,
+		Key:                a.EventKey(),
+		ConfirmationNumber: firstNonEmpty(a.SupplierConfNum, a.BookingSiteConfNum),
+		ColorID:            activityColorID,
+		Travelers:          travelerNames(a.Participants),
+		sections:           sections,
+	}, nil
+}
+
+// eventTimes returns the calendar times of an object from start to end and
+// their text for the description. They are all day dates if either has no
+// time, the end is the start if it has no date.
+func eventTimes(startDateTime, endDateTime DateTime, tz *TimezoneResolver) (calendar.EventDateTime, calendar.EventDateTime, string, string, error) {
+	if endDateTime.Date == "" {
+		endDateTime = startDateTime
+	}
+
+	if startDateTime.Time == "" || endDateTime.Time == "" {
+		start, err := time.Parse("2006-01-02", startDateTime.Date)
+		if err != nil {
+			return calendar.EventDateTime{}, calendar.EventDateTime{}, "", "", err
+		}
+		end, err := time.Parse("2006-01-02", endDateTime.Date)
+		if err != nil {
+			return calendar.EventDateTime{}, calendar.EventDateTime{}, "", "", err
+		}
+		// The end date of all day events is exclusive.
+		return calendar.EventDateTime{Date: start.Format("2006-01-02")},
+			calendar.EventDateTime{Date: end.AddDate(0, 0, 1).Format("2006-01-02")},
+			start.Format("Mon, 02 Jan 2006"), end.Format("Mon, 02 Jan 2006"), nil
+	}
+
+	start, startTimezone, err := tz.Parse(startDateTime, "")
+	if err != nil {
+		return calendar.EventDateTime{}, calendar.EventDateTime{}, "", "", err
+	}
+	end, endTimezone, err := tz.Parse(endDateTime, "")
+	if err != nil {
+		return calendar.EventDateTime{}, calendar.EventDateTime{}, "", "", err
+	}
+	return calendar.EventDateTime{DateTime: start.Format(time.RFC3339), TimeZone: startTimezone},
+		calendar.EventDateTime{DateTime: end.Format(time.RFC3339), TimeZone: endTimezone},
+		start.Format(time.RFC1123Z), end.Format(time.RFC1123Z), nil
+}
+
+// formatAddress returns the address on one line.
+func formatAddress(a Address) string {
+	if a.Address != "" {
+		return a.Address
+	}
+	var parts []string
+	for _, part := range []string{a.Addr1, a.Addr2, a.City, strings.TrimSpace(a.State + " " + a.Zip), a.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// travelerNames returns the full names of the travelers.
+func travelerNames(travelers Travelers) []string {
+	var names []string
+	for _, t := range travelers {
+		if name := t.FullName(); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,239 @@
+package tripit
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// flightsGolden is the content of the golden files of the flights, the
+// events of the flights and the error of the last one that failed.
+type flightsGolden struct {
+	Events []Event `json:"events"`
+	Error  string  `json:"error,omitempty"`
+}
+
+func TestGetFlightSegmentsAsEventsGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "flights", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) < 1 {
+		t.Fatal("no flights in testdata/flights")
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		t.Run(name, func(t *testing.T) {
+			b, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Decode it like the client does a response.
+			var resp Response
+			if err := (&Client{}).decode(b, &resp); err != nil {
+				t.Fatal(err)
+			}
+
+			g := flightsGolden{Events: []Event{}}
+			for _, flight := range resp.Flights {
+				events, err := flight.GetFlightSegmentsAsEvents(nil)
+				if err != nil {
+					g.Error = err.Error()
+					continue
+				}
+				g.Events = append(g.Events, events...)
+			}
+
+			got, err := json.MarshalIndent(g, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := strings.TrimSuffix(file, ".json") + ".golden"
+			if *update {
+				if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("events of %s differ from %s, run go test -update if the change is expected\ngot:\n%s", file, golden, got)
+			}
+		})
+	}
+}
+
+func TestObjectEvents(t *testing.T) {
+	utc := func(date, tm string) DateTime {
+		return DateTime{Date: date, Time: tm, UTCOffset: "+00:00"}
+	}
+	timed := func(s string) calendar.EventDateTime {
+		return calendar.EventDateTime{DateTime: s}
+	}
+	allDay := func(s string) calendar.EventDateTime {
+		return calendar.EventDateTime{Date: s}
+	}
+
+	tests := []struct {
+		name   string
+		events func() ([]Event, error)
+		want   []Event
+	}{
+		{
+			name: "lodging",
+			events: func() ([]Event, error) {
+				ev, err := Lodging{
+					ID: "1", TripID: "2", SupplierName: "Example Hotel", SupplierConfNum: "H1",
+					StartDateTime: utc("2030-03-01", "15:00:00"),
+					EndDateTime:   utc("2030-03-05", "11:00:00"),
+					Address:       Address{Addr1: "1 Main St", City: "New York", State: "NY", Zip: "10001"},
+					Guests:        Travelers{{FirstName: "Jess", LastName: "Frazelle"}},
+				}.GetLodgingAsEvent(nil)
+				return []Event{ev}, err
+			},
+			want: []Event{{
+				Kind: EventKindLodging, Title: "Stay at Example Hotel", Location: "1 Main St, New York, NY 10001",
+				Start: timed("2030-03-01T15:00:00Z"), End: timed("2030-03-05T11:00:00Z"),
+				ID: "2", Key: "lodging-1", ConfirmationNumber: "H1", ColorID: lodgingColorID,
+				Travelers: []string{"Jess Frazelle"},
+			}},
+		},
+		{
+			name: "lodging without times",
+			events: func() ([]Event, error) {
+				ev, err := Lodging{
+					ID: "1", TripID: "2", DisplayName: "Airbnb",
+					StartDateTime: DateTime{Date: "2030-03-01"},
+					EndDateTime:   DateTime{Date: "2030-03-05"},
+				}.GetLodgingAsEvent(nil)
+				return []Event{ev}, err
+			},
+			want: []Event{{
+				Kind: EventKindLodging, Title: "Stay at Airbnb",
+				Start: allDay("2030-03-01"), End: allDay("2030-03-06"),
+				ID: "2", Key: "lodging-1", ColorID: lodgingColorID,
+			}},
+		},
+		{
+			name: "car",
+			events: func() ([]Event, error) {
+				ev, err := Car{
+					ID: "1", TripID: "2", SupplierName: "Example Cars", BookingSiteConfNum: "C1",
+					StartDateTime:     utc("2030-03-02", "10:00:00"),
+					EndDateTime:       utc("2030-03-04", "18:00:00"),
+					StartLocationName: "JFK Airport",
+				}.GetCarAsEvent(nil)
+				return []Event{ev}, err
+			},
+			want: []Event{{
+				Kind: EventKindCar, Title: "Car rental: Example Cars", Location: "JFK Airport",
+				Start: timed("2030-03-02T10:00:00Z"), End: timed("2030-03-04T18:00:00Z"),
+				ID: "2", Key: "car-1", ConfirmationNumber: "C1", ColorID: carColorID,
+			}},
+		},
+		{
+			name: "rail",
+			events: func() ([]Event, error) {
+				return Rail{
+					ID: "1", TripID: "2", SupplierConfNum: "R1",
+					Segments: RailSegments{
+						{ID: "3", StartDateTime: utc("2030-03-03", "09:00:00"), EndDateTime: utc("2030-03-03", "12:30:00"),
+							StartStationName: "New York", EndStationName: "Boston", CarrierName: "Amtrak", TrainNumber: "2150"},
+						{ID: "4", StartDateTime: utc("2030-03-04", "17:00:00"), EndDateTime: utc("2030-03-04", "20:30:00"),
+							StartStationName: "Boston", EndStationName: "New York", ConfirmationNum: "R2"},
+					},
+				}.GetRailSegmentsAsEvents(nil)
+			},
+			want: []Event{
+				{
+					Kind: EventKindRail, Title: "Train to Boston (Amtrak 2150)", Location: "New York",
+					Start: timed("2030-03-03T09:00:00Z"), End: timed("2030-03-03T12:30:00Z"),
+					ID: "2", SegmentID: "3", Key: "rail-3", ConfirmationNumber: "R1", ColorID: railColorID,
+				},
+				{
+					Kind: EventKindRail, Title: "Train to New York", Location: "Boston",
+					Start: timed("2030-03-04T17:00:00Z"), End: timed("2030-03-04T20:30:00Z"),
+					ID: "2", SegmentID: "4", Key: "rail-4", ConfirmationNumber: "R2", ColorID: railColorID,
+				},
+			},
+		},
+		{
+			name: "activity",
+			events: func() ([]Event, error) {
+				ev, err := Activity{
+					ID: "1", TripID: "2", DisplayName: "Concert", LocationName: "Carnegie Hall",
+					StartDateTime: utc("2030-03-02", "19:30:00"), EndTime: "22:00:00",
+				}.GetActivityAsEvent(nil)
+				return []Event{ev}, err
+			},
+			want: []Event{{
+				Kind: EventKindActivity, Title: "Concert", Location: "Carnegie Hall",
+				Start: timed("2030-03-02T19:30:00Z"), End: timed("2030-03-02T22:00:00Z"),
+				ID: "2", Key: "activity-1", ColorID: activityColorID,
+			}},
+		},
+		{
+			name: "activity without an end time",
+			events: func() ([]Event, error) {
+				ev, err := Activity{
+					ID: "1", TripID: "2", DisplayName: "Tour",
+					StartDateTime: utc("2030-03-02", "10:00:00"),
+				}.GetActivityAsEvent(nil)
+				return []Event{ev}, err
+			},
+			want: []Event{{
+				Kind: EventKindActivity, Title: "Tour",
+				Start: timed("2030-03-02T10:00:00Z"), End: timed("2030-03-02T10:00:00Z"),
+				ID: "2", Key: "activity-1", ColorID: activityColorID,
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.events()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d events, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				// The descriptions are left out, they are checked by the
+				// goldens of the flights.
+				got[i].Description, got[i].sections = "", nil
+				g, _ := json.Marshal(got[i])
+				w, _ := json.Marshal(tt.want[i])
+				if !bytes.Equal(g, w) {
+					t.Errorf("event %d is\n%s\nwant\n%s", i, g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestEventKindObjectType(t *testing.T) {
+	for _, kind := range EventKinds {
+		if kind.ObjectType() == "" {
+			t.Errorf("%s events have no object type", kind)
+		}
+	}
+	if got := EventKindBuffer.ObjectType(); got != TypeFlight {
+		t.Errorf("buffer events are for %q objects, want %q", got, TypeFlight)
+	}
+}
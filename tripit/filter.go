@@ -0,0 +1,178 @@
+package tripit
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// TravelerTrue filters on objects where the user is a traveler.
+	TravelerTrue TravelerValue = "true"
+	// TravelerFalse filters on objects where the user is not a traveler.
+	TravelerFalse TravelerValue = "false"
+	// TravelerAll does not filter on the traveler.
+	TravelerAll TravelerValue = "all"
+)
+
+// TravelerValue defines the values for the traveler filter.
+type TravelerValue string
+
+// filterRule describes on which endpoints a filter is valid and how to check its value.
+type filterRule struct {
+	endpoints []string
+	validate  func(string) error
+}
+
+var filterRules = map[TypeFilter]filterRule{
+	FilterTraveler: {
+		endpoints: []string{ListTripsEndpoint, ListObjectsEndpoint},
+		validate:  oneOf(string(TravelerTrue), string(TravelerFalse), string(TravelerAll)),
+	},
+	FilterPast: {
+		endpoints: []string{ListTripsEndpoint, ListObjectsEndpoint},
+		validate:  oneOf("true", "false"),
+	},
+	FilterModifiedSince: {
+		endpoints: []string{ListTripsEndpoint, ListObjectsEndpoint},
+		validate:  isInteger,
+	},
+	FilterIncludeObjects: {
+		endpoints: []string{ListTripsEndpoint},
+		validate:  oneOf("true", "false"),
+	},
+	FilterTripID: {
+		endpoints: []string{ListObjectsEndpoint},
+		validate:  isInteger,
+	},
+	FilterType: {
+		endpoints: []string{ListObjectsEndpoint},
+		validate: oneOf(
+			string(TypeActivity),
+			string(TypeCar),
+			string(TypeCruise),
+			string(TypeDirections),
+			string(TypeFlight),
+			string(TypeLodging),
+			string(TypeMap),
+			string(TypeNote),
+			string(TypeRail),
+			string(TypeRestaurant),
+			string(TypeTransport),
+			string(TypeWeather),
+		),
+	},
+	FilterPageNum: {
+		endpoints: []string{ListTripsEndpoint, ListObjectsEndpoint},
+		validate:  isPositiveInteger,
+	},
+	FilterPageSize: {
+		endpoints: []string{ListTripsEndpoint, ListObjectsEndpoint},
+		validate:  isPositiveInteger,
+	},
+}
+
+// Past returns a filter for past (true) or upcoming (false) trips and objects.
+func Past(past bool) Filter {
+	return Filter{Type: FilterPast, Value: strconv.FormatBool(past)}
+}
+
+// ModifiedSince returns a filter for trips and objects modified after t.
+func ModifiedSince(t time.Time) Filter {
+	return Filter{Type: FilterModifiedSince, Value: strconv.FormatInt(t.Unix(), 10)}
+}
+
+// IncludeObjects returns a filter to include the objects of trips when listing trips.
+func IncludeObjects(include bool) Filter {
+	return Filter{Type: FilterIncludeObjects, Value: strconv.FormatBool(include)}
+}
+
+// TripID returns a filter for the objects of the trip with the given id.
+func TripID(id string) Filter {
+	return Filter{Type: FilterTripID, Value: id}
+}
+
+// ObjectType returns a filter for objects of the given type.
+func ObjectType(t Type) Filter {
+	return Filter{Type: FilterType, Value: string(t)}
+}
+
+// ByTraveler returns a filter on whether the user is a traveler.
+// It is not named Traveler since that is the type for a traveler.
+func ByTraveler(v TravelerValue) Filter {
+	return Filter{Type: FilterTraveler, Value: string(v)}
+}
+
+// PageNum returns a filter for the page number of the results.
+func PageNum(n int) Filter {
+	return Filter{Type: FilterPageNum, Value: strconv.Itoa(n)}
+}
+
+// PageSize returns a filter for the number of results per page.
+func PageSize(n int) Filter {
+	return Filter{Type: FilterPageSize, Value: strconv.Itoa(n)}
+}
+
+// validateFilters checks that the filters are valid for the endpoint and
+// that each filter is only passed once.
+func validateFilters(endpoint string, filters []Filter) error {
+	seen := map[TypeFilter]bool{}
+	for _, filter := range filters {
+		if filter.Type == FilterNone {
+			continue
+		}
+
+		rule, ok := filterRules[filter.Type]
+		if !ok {
+			return fmt.Errorf("unknown filter %q", filter.Type)
+		}
+
+		if !contains(rule.endpoints, endpoint) {
+			return fmt.Errorf("filter %q is not valid on %s", filter.Type, endpoint)
+		}
+
+		if seen[filter.Type] {
+			return fmt.Errorf("filter %q was passed more than once", filter.Type)
+		}
+		seen[filter.Type] = true
+
+		if err := rule.validate(filter.Value); err != nil {
+			return fmt.Errorf("filter %q has an invalid value: %v", filter.Type, err)
+		}
+	}
+
+	return nil
+}
+
+func oneOf(values ...string) func(string) error {
+	return func(v string) error {
+		if contains(values, v) {
+			return nil
+		}
+		return fmt.Errorf("%q must be one of %v", v, values)
+	}
+}
+
+func isInteger(v string) error {
+	if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+		return fmt.Errorf("%q must be an integer", v)
+	}
+	return nil
+}
+
+func isPositiveInteger(v string) error {
+	i, err := strconv.Atoi(v)
+	if err != nil || i < 1 {
+		return fmt.Errorf("%q must be a positive integer", v)
+	}
+	return nil
+}
+
+func contains(s []string, v string) bool {
+	for _, a := range s {
+		if a == v {
+			return true
+		}
+	}
+	return false
+}
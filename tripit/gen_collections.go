@@ -0,0 +1,82 @@
+//go:build ignore
+// +build ignore
+
+// gen_collections generates the JSON methods of the collection types in
+// types.go, the ones declared as a slice of another type, so they decode a
+// single object or an array.
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"text/template"
+)
+
+const (
+	typesFile  = "types.go"
+	outputFile = "collection_gen.go"
+)
+
+var methods = template.Must(template.New("methods").Parse(`// Code generated by gen_collections.go; DO NOT EDIT.
+
+package tripit
+{{range .}}
+// UnmarshalJSON builds the vector from the JSON in b.
+func (p *{{.Name}}) UnmarshalJSON(b []byte) error {
+	return unmarshalOneOrMany(b, (*[]{{.Elem}})(p))
+}
+
+// MarshalJSON encodes the vector as a single object if it has one element.
+func (p {{.Name}}) MarshalJSON() ([]byte, error) {
+	return marshalOneOrMany([]{{.Elem}}(p))
+}
+{{end}}`))
+
+type collection struct {
+	Name string
+	Elem string
+}
+
+func main() {
+	f, err := parser.ParseFile(token.NewFileSet(), typesFile, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var collections []collection
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			arr, ok := ts.Type.(*ast.ArrayType)
+			if !ok || arr.Len != nil {
+				continue
+			}
+			elem, ok := arr.Elt.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			collections = append(collections, collection{Name: ts.Name.Name, Elem: elem.Name})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := methods.Execute(&buf, collections); err != nil {
+		log.Fatal(err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(outputFile, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,182 @@
+package tripit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+const (
+	// DefaultPageSize is the default number of results per page for an Iterator.
+	DefaultPageSize = 25
+)
+
+// ErrIteratorDone is returned by Iterator.Next when there are no more pages.
+var ErrIteratorDone = errors.New("no more pages in iterator")
+
+// Iterator pages through the results of a list endpoint.
+// When past results are included it pages through the past results and
+// then the upcoming results, dropping objects it has already returned.
+type Iterator struct {
+	// PageSize is the number of results per page, defaults to DefaultPageSize.
+	PageSize int
+
+	c        *Client
+	endpoint string
+	filters  []Filter
+
+	// pasts holds the values of the past filter we still need to page through.
+	pasts []string
+	page  int
+	seen  map[string]bool
+}
+
+// TripIterator returns an Iterator over the ListTrips results for the filters.
+// If includePast is true both past and upcoming trips are returned.
+// The past, page_num and page_size filters are managed by the Iterator.
+func (c *Client) TripIterator(includePast bool, filters ...Filter) *Iterator {
+	return c.newIterator(ListTripsEndpoint, includePast, filters)
+}
+
+// ObjectIterator returns an Iterator over the ListObjects results for the filters.
+// If includePast is true both past and upcoming objects are returned.
+// The past, page_num and page_size filters are managed by the Iterator.
+func (c *Client) ObjectIterator(includePast bool, filters ...Filter) *Iterator {
+	return c.newIterator(ListObjectsEndpoint, includePast, filters)
+}
+
+func (c *Client) newIterator(endpoint string, includePast bool, filters []Filter) *Iterator {
+	// Remove the filters we manage ourselves.
+	var f []Filter
+	for _, filter := range filters {
+		switch filter.Type {
+		case FilterPast, FilterPageNum, FilterPageSize:
+			continue
+		}
+		f = append(f, filter)
+	}
+
+	pasts := []string{"false"}
+	if includePast {
+		pasts = []string{"true", "false"}
+	}
+
+	return &Iterator{
+		PageSize: DefaultPageSize,
+		c:        c,
+		endpoint: endpoint,
+		filters:  f,
+		pasts:    pasts,
+		page:     1,
+		seen:     map[string]bool{},
+	}
+}
+
+// Next returns the next page of results, the request is aborted once ctx
+// is done. It returns ErrIteratorDone when there are no more pages.
+func (it *Iterator) Next(ctx context.Context) (*Response, error) {
+	if len(it.pasts) < 1 {
+		return nil, ErrIteratorDone
+	}
+
+	pageSize := it.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	filters := append([]Filter{
+		{Type: FilterPast, Value: it.pasts[0]},
+		PageNum(it.page),
+		PageSize(pageSize),
+	}, it.filters...)
+	if err := validateFilters(it.endpoint, filters); err != nil {
+		return nil, err
+	}
+
+	resp, err := it.c.doRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%s", it.endpoint, formatFilters(filters)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Figure out if there is another page.
+	var pageNum, maxPage int
+	if resp.PageNum != "" {
+		pageNum, err = strconv.Atoi(resp.PageNum)
+		if err != nil {
+			return nil, fmt.Errorf("parsing page_num %q failed: %v", resp.PageNum, err)
+		}
+	}
+	if resp.MaxPage != "" {
+		maxPage, err = strconv.Atoi(resp.MaxPage)
+		if err != nil {
+			return nil, fmt.Errorf("parsing max_page %q failed: %v", resp.MaxPage, err)
+		}
+	}
+
+	if pageNum > 0 && pageNum < maxPage {
+		it.page = pageNum + 1
+	} else {
+		// Move on to the next past filter value.
+		it.pasts = it.pasts[1:]
+		it.page = 1
+	}
+
+	it.dedupe(resp)
+
+	return resp, nil
+}
+
+// ForEachPage calls fn for each page of results until there are no more pages,
+// fn returns an error or ctx is done.
+func (it *Iterator) ForEachPage(ctx context.Context, fn func(*Response) error) error {
+	for {
+		resp, err := it.Next(ctx)
+		if err == ErrIteratorDone {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := fn(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// dedupe removes the objects from the response that were already returned
+// by a previous page.
+func (it *Iterator) dedupe(resp *Response) {
+	v := reflect.ValueOf(resp).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.Slice || field.Type().Elem().Kind() != reflect.Struct {
+			continue
+		}
+
+		// Only objects that have an ID can be deduplicated.
+		if _, ok := field.Type().Elem().FieldByName("ID"); !ok {
+			continue
+		}
+
+		kept := reflect.MakeSlice(field.Type(), 0, field.Len())
+		for j := 0; j < field.Len(); j++ {
+			obj := field.Index(j)
+			if obj.FieldByName("ID").IsZero() {
+				kept = reflect.Append(kept, obj)
+				continue
+			}
+
+			id := fmt.Sprintf("%s/%v", v.Type().Field(i).Name, obj.FieldByName("ID").Interface())
+			if it.seen[id] {
+				continue
+			}
+			it.seen[id] = true
+			kept = reflect.Append(kept, obj)
+		}
+		field.Set(kept)
+	}
+}
@@ -0,0 +1,90 @@
+package tripit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Participant is an invitee of a trip together with their profile.
+type Participant struct {
+	Invitee Invitee
+	// Profile is nil if the profile for the invitee was not returned by TripIt.
+	Profile *Profile
+}
+
+// ShareTrip shares the trip in the TripShare with the given email addresses.
+// Set IsTraveler if they are traveling, IsReadOnly if they should not be able
+// to edit the trip, and IsSentWithDetails to include the trip details in the
+// invitation email.
+func (c *Client) ShareTrip(ctx context.Context, share TripShare, message string, emails ...string) error {
+	if share.TripID == 0 {
+		return errors.New("trip share trip_id cannot be empty")
+	}
+
+	return c.invite(ctx, Invitation{
+		EmailAddresses: EmailAddresses{Addresses: emails},
+		TripShare:      &share,
+		Message:        message,
+	})
+}
+
+// SendConnectionRequest sends a request to connect on TripIt to the given email addresses.
+func (c *Client) SendConnectionRequest(ctx context.Context, message string, emails ...string) error {
+	return c.invite(ctx, Invitation{
+		EmailAddresses:    EmailAddresses{Addresses: emails},
+		ConnectionRequest: &ConnectionRequest{},
+		Message:           message,
+	})
+}
+
+func (c *Client) invite(ctx context.Context, invitation Invitation) error {
+	if len(invitation.EmailAddresses.Addresses) < 1 {
+		return errors.New("invitation must have at least one email address")
+	}
+
+	_, err := c.Create(ctx, Request{Invitations: []Invitation{invitation}})
+	return err
+}
+
+// ListTripParticipants returns the invitees of the trip with the given id
+// resolved to their profiles.
+func (c *Client) ListTripParticipants(ctx context.Context, tripID string) ([]Participant, error) {
+	if _, err := strconv.ParseUint(tripID, 10, 64); err != nil {
+		return nil, fmt.Errorf("trip id %q must be an integer", tripID)
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf(EndpointFormatGetObject, TypeTrip, tripID, ""), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if we didn't get a result and return an error if true.
+	if len(resp.Trips) <= 0 {
+		return nil, fmt.Errorf("get trip id %s returned an empty result", tripID)
+	}
+
+	return ResolveInvitees(resp.Trips[0].Invitees, resp.Profiles), nil
+}
+
+// ResolveInvitees returns the invitees resolved to the profiles their
+// profile_ref attribute references.
+func ResolveInvitees(invitees Invitees, profiles Profiles) []Participant {
+	// Index the profiles by their reference.
+	refs := map[string]*Profile{}
+	for i := range profiles {
+		refs[profiles[i].Attributes.Ref] = &profiles[i]
+	}
+
+	participants := make([]Participant, 0, len(invitees))
+	for _, invitee := range invitees {
+		participants = append(participants, Participant{
+			Invitee: invitee,
+			Profile: refs[invitee.Attributes.ProfileRef],
+		})
+	}
+
+	return participants
+}
@@ -0,0 +1,168 @@
+package tripit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// openflightsIATAColumn is the column holding the IATA code in the openflights airports.dat file.
+	openflightsIATAColumn = 4
+	// openflightsTimezoneColumn is the column holding the tz database time zone in the openflights airports.dat file.
+	openflightsTimezoneColumn = 11
+)
+
+// TimezoneResolver resolves the time zone a DateTime should be interpreted in.
+// The order of precedence is:
+//  1. the IANA time zone name TripIt sent in DateTime.Timezone
+//  2. the time zone of the airport or station code for the DateTime
+//  3. the UTC offset TripIt sent in DateTime.UTCOffset
+//  4. the Home time zone
+//
+// A nil *TimezoneResolver is valid and uses UTC as the home time zone.
+type TimezoneResolver struct {
+	// Home is the time zone used when nothing else is known. Defaults to UTC.
+	Home *time.Location
+	// Airports maps airport or station codes to IANA time zone names.
+	Airports map[string]string
+}
+
+// NewTimezoneResolver returns a TimezoneResolver with the given home time zone.
+func NewTimezoneResolver(home *time.Location) *TimezoneResolver {
+	return &TimezoneResolver{
+		Home:     home,
+		Airports: map[string]string{},
+	}
+}
+
+// LoadAirports reads airport time zones from r, which must be in the format of
+// the openflights airports.dat file: https://openflights.org/data.html
+// Rows without an IATA code or time zone are skipped.
+func (r *TimezoneResolver) LoadAirports(rd io.Reader) error {
+	if r.Airports == nil {
+		r.Airports = map[string]string{}
+	}
+
+	cr := csv.NewReader(rd)
+	cr.FieldsPerRecord = -1
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading airports data failed: %v", err)
+		}
+
+		if len(record) <= openflightsTimezoneColumn {
+			continue
+		}
+
+		code := strings.ToUpper(strings.TrimSpace(record[openflightsIATAColumn]))
+		tz := strings.TrimSpace(record[openflightsTimezoneColumn])
+		if code == "" || code == `\N` || tz == "" || tz == `\N` {
+			continue
+		}
+
+		r.Airports[code] = tz
+	}
+}
+
+// Location returns the time zone for the DateTime d at the airport or station code.
+// It returns nil if only a UTC offset or the home time zone are known.
+func (r *TimezoneResolver) Location(d DateTime, code string) *time.Location {
+	// Prefer the time zone name TripIt gave us.
+	if d.Timezone != "" {
+		if loc, err := time.LoadLocation(d.Timezone); err == nil {
+			return loc
+		}
+	}
+
+	// Fallback to the time zone of the airport or station.
+	if r != nil && code != "" {
+		if tz, ok := r.Airports[strings.ToUpper(code)]; ok {
+			if loc, err := time.LoadLocation(tz); err == nil {
+				return loc
+			}
+		}
+	}
+
+	return nil
+}
+
+// Parse converts the DateTime d to a time.Time in the time zone resolved for
+// the airport or station code. The returned time zone name is the IANA name
+// of the location, or empty if the time is only known by its UTC offset or
+// the home time zone is time.Local, which has no IANA name.
+func (r *TimezoneResolver) Parse(d DateTime, code string) (time.Time, string, error) {
+	wall, err := time.Parse("2006-01-02T15:04:05", fmt.Sprintf("%sT%s", d.Date, d.Time))
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	if loc := r.Location(d, code); loc != nil {
+		// If TripIt gave us the UTC offset it is the most accurate way to
+		// know the instant for ambiguous wall clock times during DST changes.
+		if d.UTCOffset != "" {
+			if t, err := time.Parse(time.RFC3339, fmt.Sprintf("%sT%s%s", d.Date, d.Time, d.UTCOffset)); err == nil {
+				return t.In(loc), loc.String(), nil
+			}
+		}
+		return wallIn(wall, loc), loc.String(), nil
+	}
+
+	// Fallback to the UTC offset if we were given one.
+	if d.UTCOffset != "" {
+		t, err := time.Parse(time.RFC3339, fmt.Sprintf("%sT%s%s", d.Date, d.Time, d.UTCOffset))
+		if err != nil {
+			return time.Time{}, "", err
+		}
+		return t, "", nil
+	}
+
+	// Fallback to the home time zone.
+	home := time.UTC
+	if r != nil && r.Home != nil {
+		home = r.Home
+	}
+	if home == time.Local {
+		// "Local" is not a time zone name Google Calendar or anyone else
+		// knows, so only the offset of the time is kept.
+		return wallIn(wall, home), "", nil
+	}
+	return wallIn(wall, home), home.String(), nil
+}
+
+// wallIn returns the time with the wall clock of t in the location loc.
+// Wall clock times that fall in a daylight saving gap are moved forward
+// by the size of the gap, ambiguous times resolve to the first occurrence.
+func wallIn(t time.Time, loc *time.Location) time.Time {
+	l := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+
+	// The offset from before the transition, if there was one.
+	_, offset := l.Add(-24 * time.Hour).Zone()
+	before := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.FixedZone("", offset)).In(loc)
+
+	if sameWall(l, t) {
+		// An ambiguous time also has the wall clock with the offset from
+		// before the transition, which is the earlier of the two.
+		if sameWall(before, t) && before.Before(l) {
+			return before
+		}
+		return l
+	}
+
+	// The time is in a gap, the offset from before the transition moves it
+	// forward by the size of the gap.
+	return before
+}
+
+// sameWall returns if a and b have the same wall clock.
+func sameWall(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd && a.Hour() == b.Hour() && a.Minute() == b.Minute() && a.Second() == b.Second()
+}
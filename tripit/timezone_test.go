@@ -0,0 +1,414 @@
+package tripit
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone data for %s is not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestTimezoneResolverParse(t *testing.T) {
+	tests := []struct {
+		name string
+		d    DateTime
+		code string
+		// home is the home time zone, UTC if it is empty.
+		home     string
+		want     string
+		wantZone string
+	}{
+		// The time zone TripIt sent.
+		{
+			name:     "time zone",
+			d:        DateTime{Date: "2024-07-01", Time: "12:00:00", Timezone: "America/Los_Angeles"},
+			want:     "2024-07-01T12:00:00-07:00",
+			wantZone: "America/Los_Angeles",
+		},
+		{
+			name:     "time zone wins over the airport",
+			d:        DateTime{Date: "2024-07-01", Time: "12:00:00", Timezone: "America/Los_Angeles"},
+			code:     "JFK",
+			want:     "2024-07-01T12:00:00-07:00",
+			wantZone: "America/Los_Angeles",
+		},
+		{
+			name:     "unknown time zone falls back to the airport",
+			d:        DateTime{Date: "2024-07-01", Time: "12:00:00", Timezone: "Mars/Olympus_Mons"},
+			code:     "jfk",
+			want:     "2024-07-01T12:00:00-04:00",
+			wantZone: "America/New_York",
+		},
+		{
+			name:     "airport wins over the offset",
+			d:        DateTime{Date: "2024-07-01", Time: "12:00:00", UTCOffset: "-04:00"},
+			code:     "LHR",
+			want:     "2024-07-01T17:00:00+01:00",
+			wantZone: "Europe/London",
+		},
+		{
+			name:     "offset",
+			d:        DateTime{Date: "2024-07-01", Time: "12:00:00", UTCOffset: "+05:30"},
+			want:     "2024-07-01T12:00:00+05:30",
+			wantZone: "",
+		},
+		{
+			name:     "home",
+			d:        DateTime{Date: "2024-07-01", Time: "12:00:00"},
+			home:     "Europe/Berlin",
+			want:     "2024-07-01T12:00:00+02:00",
+			wantZone: "Europe/Berlin",
+		},
+		{
+			name:     "UTC without a home",
+			d:        DateTime{Date: "2024-07-01", Time: "12:00:00"},
+			want:     "2024-07-01T12:00:00Z",
+			wantZone: "UTC",
+		},
+
+		// Daylight saving gaps, the wall clock times that do not exist are
+		// moved forward by the size of the gap.
+		{
+			name:     "gap new york",
+			d:        DateTime{Date: "2024-03-10", Time: "02:30:00", Timezone: "America/New_York"},
+			want:     "2024-03-10T03:30:00-04:00",
+			wantZone: "America/New_York",
+		},
+		{
+			name:     "gap new york start",
+			d:        DateTime{Date: "2024-03-10", Time: "02:00:00", Timezone: "America/New_York"},
+			want:     "2024-03-10T03:00:00-04:00",
+			wantZone: "America/New_York",
+		},
+		{
+			name:     "before gap new york",
+			d:        DateTime{Date: "2024-03-10", Time: "01:59:59", Timezone: "America/New_York"},
+			want:     "2024-03-10T01:59:59-05:00",
+			wantZone: "America/New_York",
+		},
+		{
+			name:     "after gap new york",
+			d:        DateTime{Date: "2024-03-10", Time: "03:00:00", Timezone: "America/New_York"},
+			want:     "2024-03-10T03:00:00-04:00",
+			wantZone: "America/New_York",
+		},
+		{
+			name:     "gap london",
+			d:        DateTime{Date: "2024-03-31", Time: "01:30:00", Timezone: "Europe/London"},
+			want:     "2024-03-31T02:30:00+01:00",
+			wantZone: "Europe/London",
+		},
+		{
+			name:     "gap sydney",
+			d:        DateTime{Date: "2024-10-06", Time: "02:30:00", Timezone: "Australia/Sydney"},
+			want:     "2024-10-06T03:30:00+11:00",
+			wantZone: "Australia/Sydney",
+		},
+		{
+			name:     "gap santiago at midnight",
+			d:        DateTime{Date: "2024-09-08", Time: "00:30:00", Timezone: "America/Santiago"},
+			want:     "2024-09-08T01:30:00-03:00",
+			wantZone: "America/Santiago",
+		},
+		{
+			name:     "half hour gap lord howe",
+			d:        DateTime{Date: "2024-10-06", Time: "02:15:00", Timezone: "Australia/Lord_Howe"},
+			want:     "2024-10-06T02:45:00+11:00",
+			wantZone: "Australia/Lord_Howe",
+		},
+		{
+			name:     "gap of the airport",
+			d:        DateTime{Date: "2024-03-10", Time: "02:30:00"},
+			code:     "JFK",
+			want:     "2024-03-10T03:30:00-04:00",
+			wantZone: "America/New_York",
+		},
+		{
+			name:     "gap at home",
+			d:        DateTime{Date: "2024-03-10", Time: "02:30:00"},
+			home:     "America/New_York",
+			want:     "2024-03-10T03:30:00-04:00",
+			wantZone: "America/New_York",
+		},
+
+		// Daylight saving overlaps, the wall clock times that happen twice
+		// are the first one unless TripIt sent the offset.
+		{
+			name:     "overlap new york",
+			d:        DateTime{Date: "2024-11-03", Time: "01:30:00", Timezone: "America/New_York"},
+			want:     "2024-11-03T01:30:00-04:00",
+			wantZone: "America/New_York",
+		},
+		{
+			name:     "overlap new york start",
+			d:        DateTime{Date: "2024-11-03", Time: "01:00:00", Timezone: "America/New_York"},
+			want:     "2024-11-03T01:00:00-04:00",
+			wantZone: "America/New_York",
+		},
+		{
+			name:     "after overlap new york",
+			d:        DateTime{Date: "2024-11-03", Time: "02:00:00", Timezone: "America/New_York"},
+			want:     "2024-11-03T02:00:00-05:00",
+			wantZone: "America/New_York",
+		},
+		{
+			name:     "overlap new york with offset",
+			d:        DateTime{Date: "2024-11-03", Time: "01:30:00", Timezone: "America/New_York", UTCOffset: "-05:00"},
+			want:     "2024-11-03T01:30:00-05:00",
+			wantZone: "America/New_York",
+		},
+		{
+			name:     "overlap london",
+			d:        DateTime{Date: "2024-10-27", Time: "01:30:00", Timezone: "Europe/London"},
+			want:     "2024-10-27T01:30:00+01:00",
+			wantZone: "Europe/London",
+		},
+		{
+			name:     "overlap london start",
+			d:        DateTime{Date: "2024-10-27", Time: "01:00:00", Timezone: "Europe/London"},
+			want:     "2024-10-27T01:00:00+01:00",
+			wantZone: "Europe/London",
+		},
+		{
+			name:     "after overlap london",
+			d:        DateTime{Date: "2024-10-27", Time: "02:00:00", Timezone: "Europe/London"},
+			want:     "2024-10-27T02:00:00Z",
+			wantZone: "Europe/London",
+		},
+		{
+			name:     "overlap london with offset",
+			d:        DateTime{Date: "2024-10-27", Time: "01:30:00", Timezone: "Europe/London", UTCOffset: "+00:00"},
+			want:     "2024-10-27T01:30:00Z",
+			wantZone: "Europe/London",
+		},
+		{
+			name:     "overlap sydney",
+			d:        DateTime{Date: "2024-04-07", Time: "02:30:00", Timezone: "Australia/Sydney"},
+			want:     "2024-04-07T02:30:00+11:00",
+			wantZone: "Australia/Sydney",
+		},
+		{
+			name:     "overlap santiago before midnight",
+			d:        DateTime{Date: "2024-04-06", Time: "23:30:00", Timezone: "America/Santiago"},
+			want:     "2024-04-06T23:30:00-03:00",
+			wantZone: "America/Santiago",
+		},
+		{
+			name:     "half hour overlap lord howe",
+			d:        DateTime{Date: "2024-04-07", Time: "01:45:00", Timezone: "Australia/Lord_Howe"},
+			want:     "2024-04-07T01:45:00+11:00",
+			wantZone: "Australia/Lord_Howe",
+		},
+		{
+			name:     "overlap of the airport",
+			d:        DateTime{Date: "2024-10-27", Time: "01:30:00"},
+			code:     "LHR",
+			want:     "2024-10-27T01:30:00+01:00",
+			wantZone: "Europe/London",
+		},
+		{
+			name:     "overlap at home",
+			d:        DateTime{Date: "2024-10-27", Time: "01:30:00"},
+			home:     "Europe/London",
+			want:     "2024-10-27T01:30:00+01:00",
+			wantZone: "Europe/London",
+		},
+
+		// Samoa moved across the date line and skipped December 30, 2011.
+		{
+			name:     "skipped day apia",
+			d:        DateTime{Date: "2011-12-30", Time: "12:00:00", Timezone: "Pacific/Apia"},
+			want:     "2011-12-31T12:00:00+14:00",
+			wantZone: "Pacific/Apia",
+		},
+		{
+			name:     "before skipped day apia",
+			d:        DateTime{Date: "2011-12-29", Time: "23:30:00", Timezone: "Pacific/Apia"},
+			want:     "2011-12-29T23:30:00-10:00",
+			wantZone: "Pacific/Apia",
+		},
+		{
+			name:     "after skipped day apia",
+			d:        DateTime{Date: "2011-12-31", Time: "00:00:00", Timezone: "Pacific/Apia"},
+			want:     "2011-12-31T00:00:00+14:00",
+			wantZone: "Pacific/Apia",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := time.UTC
+			if tt.home != "" {
+				home = mustLoadLocation(t, tt.home)
+			}
+			if tt.wantZone != "" {
+				mustLoadLocation(t, tt.wantZone)
+			}
+
+			r := NewTimezoneResolver(home)
+			r.Airports = map[string]string{
+				"JFK": "America/New_York",
+				"LHR": "Europe/London",
+			}
+
+			got, zone, err := r.Parse(tt.d, tt.code)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Format(time.RFC3339) != tt.want {
+				t.Errorf("Parse() = %s, want %s", got.Format(time.RFC3339), tt.want)
+			}
+			if zone != tt.wantZone {
+				t.Errorf("Parse() time zone = %q, want %q", zone, tt.wantZone)
+			}
+		})
+	}
+}
+
+func TestTimezoneResolverParseLocal(t *testing.T) {
+	// The --timezone flag and the account timezone default to Local.
+	home, err := time.LoadLocation("Local")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewTimezoneResolver(home)
+	r.Airports = map[string]string{"JFK": "America/New_York"}
+
+	got, zone, err := r.Parse(DateTime{Date: "2024-07-01", Time: "12:00:00"}, "SFO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, time.July, 1, 12, 0, 0, 0, time.Local); !got.Equal(want) {
+		t.Errorf("Parse() = %s, want %s", got.Format(time.RFC3339), want.Format(time.RFC3339))
+	}
+	if zone != "" {
+		t.Errorf("Parse() time zone = %q, want none for the local time zone", zone)
+	}
+}
+
+func TestTimezoneResolverParseDateLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end DateTime
+		want       time.Duration
+	}{
+		{
+			// Arrives two calendar days after it leaves.
+			name:  "westbound SFO to HKG",
+			start: DateTime{Date: "2024-03-14", Time: "23:55:00", Timezone: "America/Los_Angeles"},
+			end:   DateTime{Date: "2024-03-16", Time: "06:00:00", Timezone: "Asia/Hong_Kong"},
+			want:  15*time.Hour + 5*time.Minute,
+		},
+		{
+			// Arrives on the same calendar day, at an earlier wall clock time.
+			name:  "eastbound SYD to HNL",
+			start: DateTime{Date: "2024-03-15", Time: "21:00:00", Timezone: "Australia/Sydney"},
+			end:   DateTime{Date: "2024-03-15", Time: "10:00:00", Timezone: "Pacific/Honolulu"},
+			want:  10 * time.Hour,
+		},
+		{
+			// Leaves at night and lands the morning of the same calendar day.
+			name:  "eastbound AKL to HNL",
+			start: DateTime{Date: "2024-03-15", Time: "22:00:00", Timezone: "Pacific/Auckland"},
+			end:   DateTime{Date: "2024-03-15", Time: "08:00:00", Timezone: "Pacific/Honolulu"},
+			want:  9 * time.Hour,
+		},
+		{
+			// Kiritimati and Honolulu have the same wall clock on either side
+			// of the date line, it arrives on the calendar day before it
+			// leaves.
+			name:  "eastbound CXI to HNL",
+			start: DateTime{Date: "2024-03-15", Time: "08:00:00", Timezone: "Pacific/Kiritimati"},
+			end:   DateTime{Date: "2024-03-14", Time: "10:00:00", Timezone: "Pacific/Honolulu"},
+			want:  2 * time.Hour,
+		},
+		{
+			name:  "offsets only",
+			start: DateTime{Date: "2024-03-15", Time: "22:00:00", UTCOffset: "+13:00"},
+			end:   DateTime{Date: "2024-03-15", Time: "08:00:00", UTCOffset: "-10:00"},
+			want:  9 * time.Hour,
+		},
+		{
+			// Across the day Samoa skipped.
+			name:  "apia",
+			start: DateTime{Date: "2011-12-29", Time: "23:30:00", Timezone: "Pacific/Apia"},
+			end:   DateTime{Date: "2011-12-31", Time: "00:00:00", Timezone: "Pacific/Apia"},
+			want:  30 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, d := range []DateTime{tt.start, tt.end} {
+				if d.Timezone != "" {
+					mustLoadLocation(t, d.Timezone)
+				}
+			}
+
+			start, _, err := (*TimezoneResolver)(nil).Parse(tt.start, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			end, _, err := (*TimezoneResolver)(nil).Parse(tt.end, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := end.Sub(start); got != tt.want {
+				t.Errorf("flight takes %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimezoneResolverParseErrors(t *testing.T) {
+	tests := []DateTime{
+		{Date: "2024-03-15"},
+		{Date: "2024-02-30", Time: "10:00:00", Timezone: "UTC"},
+		{Date: "15/03/2024", Time: "10:00:00"},
+		{Date: "2024-03-15", Time: "10:00:00", UTCOffset: "EST"},
+	}
+	for _, d := range tests {
+		if got, _, err := (*TimezoneResolver)(nil).Parse(d, ""); err == nil {
+			t.Errorf("Parse(%+v) = %s, want an error", d, got)
+		}
+	}
+}
+
+func TestTimezoneResolverLoadAirports(t *testing.T) {
+	// The rows of the openflights airports.dat file, one without an IATA
+	// code and one without a time zone.
+	data := strings.Join([]string{
+		`3469,"San Francisco International Airport","San Francisco","United States","SFO","KSFO",37.61899948120117,-122.375,13,-8,"A","America/Los_Angeles","airport","OurAirports"`,
+		`3797,"John F Kennedy International Airport","New York","United States","JFK","KJFK",40.63980103,-73.77890015,13,-5,"A","America/New_York","airport","OurAirports"`,
+		`9999,"No Code","Nowhere","Nowhere",\N,"XXXX",0,0,0,0,"U","Europe/London","airport","OurAirports"`,
+		`9998,"No Time Zone","Nowhere","Nowhere","NTZ","XXXY",0,0,0,0,"U",\N,"airport","OurAirports"`,
+		`short,row`,
+	}, "\n")
+
+	r := NewTimezoneResolver(time.UTC)
+	if err := r.LoadAirports(strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"SFO": "America/Los_Angeles",
+		"JFK": "America/New_York",
+	}
+	if len(r.Airports) != len(want) {
+		t.Errorf("loaded %d airports, want %d: %v", len(r.Airports), len(want), r.Airports)
+	}
+	for code, tz := range want {
+		if r.Airports[code] != tz {
+			t.Errorf("airport %s is in %q, want %q", code, r.Airports[code], tz)
+		}
+	}
+}
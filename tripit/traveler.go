@@ -0,0 +1,49 @@
+package tripit
+
+import (
+	"strings"
+)
+
+// FullName returns the full name of the traveler.
+func (t Traveler) FullName() string {
+	return strings.Join(strings.Fields(strings.Join([]string{t.FirstName, t.MiddleName, t.LastName}, " ")), " ")
+}
+
+// IsTraveling reports whether the user with the profile is traveling on the
+// trip. A trip shared with the user lists them as an invitee by their
+// profile ref, a trip that does not is their own.
+func (t Trip) IsTraveling(p Profile) bool {
+	for _, invitee := range t.Invitees {
+		if p.Attributes.Ref != "" && invitee.Attributes.ProfileRef == p.Attributes.Ref {
+			return invitee.IsTraveler
+		}
+	}
+
+	return true
+}
+
+// TravelerName returns the name of the first invitee traveling on the trip
+// other than the user with the profile. The profiles of the invitees are
+// the ones TripIt sends with the trips, it returns an empty string if none
+// of them is traveling.
+func (t Trip) TravelerName(p Profile, profiles Profiles) string {
+	for _, invitee := range t.Invitees {
+		if !invitee.IsTraveler || invitee.Attributes.ProfileRef == p.Attributes.Ref {
+			continue
+		}
+
+		for _, profile := range profiles {
+			if profile.Attributes.Ref != invitee.Attributes.ProfileRef {
+				continue
+			}
+			if profile.PublicDisplayName != "" {
+				return profile.PublicDisplayName
+			}
+			if profile.ScreenName != "" {
+				return profile.ScreenName
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,111 @@
+package tripit
+
+import "testing"
+
+func TestTripIsTraveling(t *testing.T) {
+	profile := Profile{Attributes: ProfileAttributes{Ref: "JESS"}}
+	invitee := func(ref string, traveler bool) Invitee {
+		return Invitee{IsTraveler: traveler, Attributes: InviteeAttributes{ProfileRef: ref}}
+	}
+
+	tests := []struct {
+		name    string
+		trip    Trip
+		profile Profile
+		want    bool
+	}{
+		{
+			name:    "own trip",
+			trip:    Trip{},
+			profile: profile,
+			want:    true,
+		},
+		{
+			name:    "own trip shared with others",
+			trip:    Trip{Invitees: Invitees{invitee("ALEX", true)}},
+			profile: profile,
+			want:    true,
+		},
+		{
+			name:    "invited as a traveler",
+			trip:    Trip{Invitees: Invitees{invitee("ALEX", true), invitee("JESS", true)}},
+			profile: profile,
+			want:    true,
+		},
+		{
+			name:    "invited without traveling",
+			trip:    Trip{Invitees: Invitees{invitee("ALEX", true), invitee("JESS", false)}},
+			profile: profile,
+			want:    false,
+		},
+		{
+			name:    "profile without a ref",
+			trip:    Trip{Invitees: Invitees{invitee("", false)}},
+			profile: Profile{},
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.trip.IsTraveling(tt.profile); got != tt.want {
+				t.Errorf("IsTraveling() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTripTravelerName(t *testing.T) {
+	profile := Profile{Attributes: ProfileAttributes{Ref: "JESS"}, PublicDisplayName: "Jess Frazelle"}
+	profiles := Profiles{
+		profile,
+		{Attributes: ProfileAttributes{Ref: "ALEX"}, PublicDisplayName: "Alex Traveler", ScreenName: "alex"},
+		{Attributes: ProfileAttributes{Ref: "SAM"}, ScreenName: "sam"},
+	}
+	invitee := func(ref string, traveler bool) Invitee {
+		return Invitee{IsTraveler: traveler, Attributes: InviteeAttributes{ProfileRef: ref}}
+	}
+
+	tests := []struct {
+		name     string
+		invitees Invitees
+		want     string
+	}{
+		{
+			name:     "display name",
+			invitees: Invitees{invitee("JESS", false), invitee("ALEX", true)},
+			want:     "Alex Traveler",
+		},
+		{
+			name:     "screen name",
+			invitees: Invitees{invitee("SAM", true)},
+			want:     "sam",
+		},
+		{
+			name:     "not the user",
+			invitees: Invitees{invitee("JESS", true), invitee("SAM", true)},
+			want:     "sam",
+		},
+		{
+			name:     "not traveling",
+			invitees: Invitees{invitee("ALEX", false)},
+			want:     "",
+		},
+		{
+			name:     "unknown profile",
+			invitees: Invitees{invitee("KIM", true)},
+			want:     "",
+		},
+		{
+			name: "no invitees",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trip := Trip{Invitees: tt.invitees}
+			if got := trip.TravelerName(profile, profiles); got != tt.want {
+				t.Errorf("TravelerName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
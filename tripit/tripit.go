@@ -2,59 +2,139 @@ package tripit
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// FormatJSON is the JSON wire format of the TripIt API.
+	FormatJSON Format = "json"
+	// FormatXML is the XML wire format of the TripIt API.
+	FormatXML Format = "xml"
+)
+
+// redacted replaces the credentials in the logs.
+const redacted = "[REDACTED]"
+
+// Format defines the wire format used to talk to the TripIt API.
+type Format string
+
 // Client holds the information needed for TripIt API authentication.
 type Client struct {
 	username string
 	password string
+
+	format Format
+
+	baseURL    string
+	httpClient *http.Client
+
+	observer RequestObserver
+}
+
+// RequestObserver is called after every request to the TripIt API with the
+// endpoint without object IDs, for example "list/trip" or "get/air", and the
+// status code of the response. The status code is 0 if the request failed
+// before there was a response.
+type RequestObserver func(endpoint string, statusCode int)
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithFormat sets the wire format the client uses for requests and responses.
+// The default is FormatJSON.
+func WithFormat(format Format) Option {
+	return func(c *Client) {
+		c.format = format
+	}
+}
+
+// WithBaseURL sets the URL of the TripIt API, without the version. The
+// default is APIUri.
+func WithBaseURL(uri string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(uri, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for the requests. The default is
+// http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithRequestObserver sets the function called after every request.
+func WithRequestObserver(observer RequestObserver) Option {
+	return func(c *Client) {
+		c.observer = observer
+	}
 }
 
 // New creates a new TripIt API client.
-func New(username, password string) *Client {
-	return &Client{
+func New(username, password string, opts ...Option) *Client {
+	c := &Client{
 		username: username,
 		password: password,
+		format:   FormatJSON,
+
+		baseURL:    APIUri,
+		httpClient: http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
-}
 
-func (c *Client) doRequest(method, endpoint string, data interface{}) (*Response, error) {
-	client := http.DefaultClient
+	return c
+}
 
+// doRequest does the request to the endpoint, it is aborted once ctx is done.
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, data interface{}) (*Response, error) {
 	// Encode data if we are passed an object.
+	// The TripIt API expects it in the json or xml parameter of a form.
 	b := bytes.NewBuffer(nil)
 	if data != nil {
-		// Create the encoder.
-		enc := json.NewEncoder(b)
-		if err := enc.Encode(data); err != nil {
-			return nil, fmt.Errorf("json encoding data for doRequest failed: %v", err)
+		d, err := c.encode(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s encoding data for doRequest failed: %v", c.format, err)
 		}
+		b.WriteString(url.Values{string(c.format): []string{string(d)}}.Encode())
 	}
 
 	// Create the request.
-	uri := fmt.Sprintf("%s/%s/%s/format/json", APIUri, APIVersion, strings.Trim(endpoint, "/"))
-	req, err := http.NewRequest(method, uri, b)
+	uri := fmt.Sprintf("%s/%s/%s/format/%s", c.baseURL, APIVersion, strings.Trim(endpoint, "/"), c.format)
+	logrus.Debugf("%s request to %s: body -> %s", method, uri, c.redact(b.String()))
+	req, err := http.NewRequestWithContext(ctx, method, uri, b)
 	if err != nil {
 		return nil, fmt.Errorf("creating %s request to %s failed: %v", method, uri, err)
 	}
 
+	if data != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
 	// Set the basic auth credentials.
 	req.SetBasicAuth(c.username, c.password)
 
 	// Do the request.
-	resp, err := client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		c.observe(endpoint, 0)
 		return nil, fmt.Errorf("performing %s request to %s failed: %v", method, uri, err)
 	}
 	defer resp.Body.Close()
+	c.observe(endpoint, resp.StatusCode)
 
 	// Check that the response status code was OK.
 	if resp.StatusCode != http.StatusOK {
@@ -82,20 +162,19 @@ func (c *Client) doRequest(method, endpoint string, data interface{}) (*Response
 			message = "The TripIt API is currently undergoing maintenance and is not available."
 		}
 
-		return nil, fmt.Errorf("%s request to %s returned status code %d: message -> %s\nbody -> %s", method, uri, resp.StatusCode, message, string(body))
+		return nil, fmt.Errorf("%s request to %s returned status code %d: message -> %s\nbody -> %s", method, uri, resp.StatusCode, message, c.redact(string(body)))
+	}
+	// Read the body of the response.
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response from %s request to %s failed: %v", method, uri, err)
 	}
-	/*body, _ := ioutil.ReadAll(resp.Body)
-	var out bytes.Buffer
-	json.Indent(&out, body, "", "  ")
-	logrus.Fatalf("body: %s", string(body))*/
+	logrus.Debugf("%s request to %s returned status code %d: body -> %s", method, uri, resp.StatusCode, c.redact(string(body)))
 
 	// Decode the response into a TripIt Response object.
 	var r Response
-	if err := decodeResponse(resp, &r); err != nil {
-		// Read the body of the request, ignore the error since we are already in the error state.
-		body, _ := ioutil.ReadAll(resp.Body)
-
-		return nil, fmt.Errorf("decoding response from %s request to %s failed: body -> %s\nerr -> %v", method, uri, string(body), err)
+	if err := c.decode(body, &r); err != nil {
+		return nil, fmt.Errorf("decoding response from %s request to %s failed: body -> %s\nerr -> %v", method, uri, c.redact(string(body)), err)
 	}
 
 	// Log warnings on the API warnings.
@@ -111,13 +190,51 @@ func (c *Client) doRequest(method, endpoint string, data interface{}) (*Response
 	return &r, nil
 }
 
-func decodeResponse(resp *http.Response, v interface{}) error {
-	// Copy buffer and change "@attributes" to "_attributes" since the json package doesn't support "@".
-	buf := new(bytes.Buffer)
-	if _, err := io.Copy(buf, resp.Body); err != nil {
-		return err
+func (c *Client) observe(endpoint string, statusCode int) {
+	if c.observer == nil {
+		return
+	}
+
+	// Only keep the operation and the object type, so the IDs and filters
+	// are not part of the endpoint.
+	parts := strings.Split(strings.Trim(endpoint, "/"), "/")
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	c.observer(strings.Join(parts, "/"), statusCode)
+}
+
+// redact replaces the credentials of the client in s, so it can be logged.
+func (c *Client) redact(s string) string {
+	if c.password == "" {
+		return s
+	}
+
+	basic := base64.StdEncoding.EncodeToString([]byte(c.username + ":" + c.password))
+	return strings.NewReplacer(
+		basic, redacted,
+		c.password, redacted,
+		url.QueryEscape(c.password), redacted,
+	).Replace(s)
+}
+
+// encode encodes the data in the wire format of the client.
+func (c *Client) encode(data interface{}) ([]byte, error) {
+	if c.format == FormatXML {
+		return xml.Marshal(data)
+	}
+
+	return json.Marshal(data)
+}
+
+// decode decodes the body in the wire format of the client into v.
+func (c *Client) decode(body []byte, v interface{}) error {
+	if c.format == FormatXML {
+		return xml.Unmarshal(body, v)
 	}
-	b := bytes.Replace(buf.Bytes(), []byte(`"@attributes"`), []byte(`"_attributes"`), -1)
+
+	// Change "@attributes" to "_attributes" since the json package doesn't support "@".
+	b := bytes.Replace(body, []byte(`"@attributes"`), []byte(`"_attributes"`), -1)
 
 	return json.Unmarshal(b, v)
 }
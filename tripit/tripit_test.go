@@ -0,0 +1,51 @@
+package tripit
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestClientRedact(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+	}{
+		{name: "plain", password: "59f6asdfasdfasdf0"},
+		{name: "escaped", password: "p@ss w0rd&=+/?"},
+		{name: "unicode", password: "pässwörd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New("jess@example.com", tt.password)
+			basic := base64.StdEncoding.EncodeToString([]byte("jess@example.com:" + tt.password))
+
+			// The ways the password can end up in a request or response
+			// that is logged.
+			s := strings.Join([]string{
+				"Authorization: Basic " + basic,
+				"password=" + url.QueryEscape(tt.password),
+				`{"password": "` + tt.password + `"}`,
+			}, "\n")
+			got := c.redact(s)
+
+			for _, secret := range []string{basic, tt.password, url.QueryEscape(tt.password)} {
+				if strings.Contains(got, secret) {
+					t.Errorf("redacted %q still has %q", got, secret)
+				}
+			}
+			if n := strings.Count(got, redacted); n != 3 {
+				t.Errorf("redacted %q has %d %s, want 3", got, n, redacted)
+			}
+			if !strings.HasPrefix(got, "Authorization: Basic ") {
+				t.Errorf("redacted %q lost the text around the credentials", got)
+			}
+		})
+	}
+
+	// Without a password there is nothing to redact.
+	if got := New("jess@example.com", "").redact("password="); got != "password=" {
+		t.Errorf("redacted %q without a password, want it unchanged", got)
+	}
+}
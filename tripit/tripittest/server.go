@@ -0,0 +1,866 @@
+// Package tripittest provides a fake of the TripIt v1 API for tests, so the
+// tripit client and the sync can run without the network.
+package tripittest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/tripit"
+)
+
+const (
+	// DefaultUsername is the username the Server accepts by default.
+	DefaultUsername = "traveler@example.com"
+	// DefaultPassword is the password the Server accepts by default.
+	DefaultPassword = "hunter2"
+	// DefaultPageSize is the page size of the list endpoints if the request
+	// has no page_size filter. It is small so paging is exercised.
+	DefaultPageSize = 5
+)
+
+// objectFields maps the object types to their field in tripit.Response.
+var objectFields = map[tripit.Type]string{
+	tripit.TypeActivity:   "Activities",
+	tripit.TypeCar:        "Cars",
+	tripit.TypeCruise:     "Cruises",
+	tripit.TypeDirections: "Directions",
+	tripit.TypeFlight:     "Flights",
+	tripit.TypeLodging:    "Lodging",
+	tripit.TypeMap:        "Maps",
+	tripit.TypeNote:       "Notes",
+	tripit.TypeRail:       "Rails",
+	tripit.TypeRestaurant: "Restaurants",
+	tripit.TypeTransport:  "Transports",
+	tripit.TypeWeather:    "Weather",
+}
+
+// requestFields maps the object types to their field in tripit.Request.
+var requestFields = map[tripit.Type]string{
+	tripit.TypeTrip:       "Trip",
+	tripit.TypeActivity:   "Activity",
+	tripit.TypeCar:        "Car",
+	tripit.TypeCruise:     "Cruise",
+	tripit.TypeDirections: "Directions",
+	tripit.TypeFlight:     "Flight",
+	tripit.TypeLodging:    "Lodging",
+	tripit.TypeMap:        "Map",
+	tripit.TypeNote:       "Note",
+	tripit.TypeRail:       "Rail",
+	tripit.TypeRestaurant: "Restaurant",
+	tripit.TypeTransport:  "Transport",
+}
+
+// objectTypes is the order the object types are listed in.
+var objectTypes = []tripit.Type{
+	tripit.TypeActivity,
+	tripit.TypeFlight,
+	tripit.TypeCar,
+	tripit.TypeCruise,
+	tripit.TypeDirections,
+	tripit.TypeLodging,
+	tripit.TypeMap,
+	tripit.TypeNote,
+	tripit.TypeRail,
+	tripit.TypeRestaurant,
+	tripit.TypeTransport,
+	tripit.TypeWeather,
+}
+
+// Server is a fake of the TripIt v1 API. It keeps the trips and objects in
+// memory and serves them in the JSON or XML format like TripIt does, with
+// paging, a single object instead of an array for collections of one, and
+// "@attributes" in JSON.
+type Server struct {
+	*httptest.Server
+
+	// Username and Password are the basic auth credentials the server
+	// accepts, the other requests get a 401.
+	Username string
+	Password string
+	// PageSize is the page size of the list endpoints if the request has no
+	// page_size filter.
+	PageSize int
+	// Now returns the time the past filter is relative to, it defaults to
+	// time.Now.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	db       tripit.Response
+	nextID   int
+	warnings []tripit.Warning
+	errors   []tripit.Error
+	faults   []*fault
+	requests []Request
+}
+
+// Request is a request the Server received.
+type Request struct {
+	Method string
+	// Endpoint is the path without the version and format, for example
+	// "list/trip/past/false".
+	Endpoint string
+	Format   tripit.Format
+	// Body is the decoded json or xml form value of a POST.
+	Body string
+}
+
+// fault is an injected failure of the requests to an endpoint.
+type fault struct {
+	prefix     string
+	statusCode int
+	remaining  int
+}
+
+// NewServer starts a Server with the default credentials and an empty
+// profile. The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		Username: DefaultUsername,
+		Password: DefaultPassword,
+		PageSize: DefaultPageSize,
+		Now:      time.Now,
+		nextID:   1000,
+	}
+	s.db.Profiles = tripit.Profiles{{
+		Attributes: tripit.ProfileAttributes{Ref: "PROFILEREF"},
+		ScreenName: "traveler",
+		IsClient:   true,
+		ProfileEmailAddresses: tripit.ProfileEmailAddresses{{
+			Address:   DefaultUsername,
+			IsPrimary: true,
+		}},
+	}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// TripItClient returns a tripit.Client for the server with its credentials.
+func (s *Server) TripItClient(opts ...tripit.Option) *tripit.Client {
+	opts = append([]tripit.Option{
+		tripit.WithBaseURL(s.URL),
+		tripit.WithHTTPClient(s.Client()),
+	}, opts...)
+	return tripit.New(s.Username, s.Password, opts...)
+}
+
+// SetProfile replaces the profile of the authenticated user.
+func (s *Server) SetProfile(profile tripit.Profile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.db.Profiles[0] = profile
+}
+
+// AddProfile adds the profile of another TripIt user, for example one a
+// trip is shared with. Like TripIt, list/trip sends the profiles of the
+// invitees of the trips with them.
+func (s *Server) AddProfile(profile tripit.Profile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.db.Profiles = append(s.db.Profiles, profile)
+}
+
+// AddTrip adds the trip and returns it with its ID.
+func (s *Server) AddTrip(trip tripit.Trip) tripit.Trip {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if trip.ID == "" {
+		trip.ID = s.newID()
+	}
+	s.db.Trips = append(s.db.Trips, trip)
+	return trip
+}
+
+// AddFlight adds the flight and returns it with the IDs of the flight and its
+// segments.
+func (s *Server) AddFlight(flight tripit.Flight) tripit.Flight {
+	return s.AddObject(tripit.TypeFlight, flight).(tripit.Flight)
+}
+
+// AddObject adds the object of the type, for example a tripit.Lodging for
+// tripit.TypeLodging, and returns it with its IDs.
+func (s *Server) AddObject(typ tripit.Type, obj interface{}) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := reflect.New(reflect.TypeOf(obj)).Elem()
+	v.Set(reflect.ValueOf(obj))
+	s.assignIDs(v)
+
+	field := s.objects(typ)
+	field.Set(reflect.Append(field, v))
+	return v.Interface()
+}
+
+// AddPointsProgram adds the points program and returns it with its ID.
+func (s *Server) AddPointsProgram(program tripit.PointsProgram) tripit.PointsProgram {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if program.ID == 0 {
+		id, _ := strconv.ParseUint(s.newID(), 10, 64)
+		program.ID = uint(id)
+	}
+	s.db.PointsPrograms = append(s.db.PointsPrograms, program)
+	return program
+}
+
+// Trips returns the trips on the server.
+func (s *Server) Trips() []tripit.Trip {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]tripit.Trip(nil), s.db.Trips...)
+}
+
+// Flights returns the flights on the server.
+func (s *Server) Flights() []tripit.Flight {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]tripit.Flight(nil), s.db.Flights...)
+}
+
+// Warn adds the warning to the next response that succeeds.
+func (s *Server) Warn(description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.warnings = append(s.warnings, tripit.Warning{
+		Description: description,
+		EntityType:  "Response",
+		Timestamp:   s.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// Error adds the error to the next response that succeeds. TripIt returns
+// the errors that do not fail the whole request with a 200.
+func (s *Server) Error(code int, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors = append(s.errors, newError(code, description, s.Now()))
+}
+
+// Fail makes the next n requests to the endpoints starting with prefix, for
+// example "list/trip" or "get/air", fail with the status code. A negative n
+// fails them until Recover is called.
+func (s *Server) Fail(prefix string, statusCode, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &fault{
+		prefix:     strings.Trim(prefix, "/"),
+		statusCode: statusCode,
+		remaining:  n,
+	})
+}
+
+// Recover removes the faults injected with Fail.
+func (s *Server) Recover() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Requests returns the requests the server received, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The path is /v1/<endpoint>/format/<format>.
+	path := strings.Trim(r.URL.Path, "/")
+	if !strings.HasPrefix(path, tripit.APIVersion+"/") {
+		s.writeError(w, tripit.FormatJSON, http.StatusNotFound, "unknown API version")
+		return
+	}
+	path = strings.TrimPrefix(path, tripit.APIVersion+"/")
+
+	format := tripit.FormatXML
+	if i := strings.LastIndex(path, "/format/"); i >= 0 {
+		format = tripit.Format(path[i+len("/format/"):])
+		path = path[:i]
+	}
+	if format != tripit.FormatJSON && format != tripit.FormatXML {
+		s.writeError(w, tripit.FormatJSON, http.StatusBadRequest, fmt.Sprintf("unknown format %q", format))
+		return
+	}
+
+	req := Request{Method: r.Method, Endpoint: path, Format: format}
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			s.writeError(w, format, http.StatusBadRequest, err.Error())
+			return
+		}
+		req.Body = r.PostForm.Get(string(format))
+	}
+	s.requests = append(s.requests, req)
+
+	if username, password, ok := r.BasicAuth(); !ok || username != s.Username || password != s.Password {
+		s.writeError(w, format, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	for _, f := range s.faults {
+		if f.remaining == 0 || !strings.HasPrefix(path, f.prefix) {
+			continue
+		}
+		f.remaining--
+		s.writeError(w, format, f.statusCode, http.StatusText(f.statusCode))
+		return
+	}
+
+	parts := strings.Split(path, "/")
+	var (
+		resp *tripit.Response
+		code int
+		err  error
+	)
+	switch {
+	case path == "list/trip" || strings.HasPrefix(path, "list/trip/"):
+		resp, code, err = s.listTrips(filters(parts[2:]))
+	case path == "list/object" || strings.HasPrefix(path, "list/object/"):
+		resp, code, err = s.listObjects(filters(parts[2:]))
+	case path == "list/points_program" || strings.HasPrefix(path, "list/points_program/"):
+		resp = &tripit.Response{PointsPrograms: s.db.PointsPrograms}
+	case path == "get/profile":
+		resp = &tripit.Response{Profiles: s.db.Profiles[:1]}
+	case len(parts) >= 4 && parts[0] == "get" && parts[2] == "id":
+		resp, code, err = s.get(tripit.Type(parts[1]), parts[3], filters(parts[4:]))
+	case len(parts) == 4 && parts[0] == "delete" && parts[2] == "id":
+		resp, code, err = s.delete(tripit.Type(parts[1]), parts[3])
+	case len(parts) == 4 && parts[0] == "replace" && parts[2] == "id" && r.Method == http.MethodPost:
+		resp, code, err = s.replace(tripit.Type(parts[1]), parts[3], format, req.Body)
+	case path == "create" && r.Method == http.MethodPost:
+		resp, code, err = s.create(format, req.Body)
+	default:
+		code, err = http.StatusNotFound, fmt.Errorf("unknown endpoint %s %s", r.Method, path)
+	}
+	if err != nil {
+		s.writeError(w, format, code, err.Error())
+		return
+	}
+
+	resp.Warnings = append(resp.Warnings, s.warnings...)
+	resp.Errors = append(resp.Errors, s.errors...)
+	s.warnings, s.errors = nil, nil
+
+	s.write(w, format, http.StatusOK, resp)
+}
+
+// listTrips serves list/trip.
+func (s *Server) listTrips(f map[string]string) (*tripit.Response, int, error) {
+	traveler, ok := f["traveler"]
+	if !ok {
+		traveler = "true"
+	}
+
+	var trips []tripit.Trip
+	for _, t := range s.db.Trips {
+		if s.isPast(t) != (f["past"] == "true") {
+			continue
+		}
+		switch traveler {
+		case "true":
+			if !t.IsTraveling(s.db.Profiles[0]) {
+				continue
+			}
+		case "false":
+			if t.IsTraveling(s.db.Profiles[0]) {
+				continue
+			}
+		case "all":
+		default:
+			return nil, http.StatusBadRequest, fmt.Errorf("unknown traveler filter %q", traveler)
+		}
+		trips = append(trips, t)
+	}
+
+	resp := &tripit.Response{}
+	lo, hi, err := s.page(resp, f, len(trips))
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	resp.Trips = trips[lo:hi]
+	resp.Profiles = s.inviteeProfiles(resp.Trips)
+
+	if f["include_objects"] == "true" {
+		for _, t := range resp.Trips {
+			s.appendObjects(resp, func(typ tripit.Type, obj reflect.Value) bool {
+				return obj.FieldByName("TripID").String() == t.ID
+			})
+		}
+	}
+
+	return resp, http.StatusOK, nil
+}
+
+// listObjects serves list/object.
+func (s *Server) listObjects(f map[string]string) (*tripit.Response, int, error) {
+	past := f["past"] == "true"
+	match := func(typ tripit.Type, obj reflect.Value) bool {
+		if t, ok := f["type"]; ok && tripit.Type(t) != typ {
+			return false
+		}
+		tripID := obj.FieldByName("TripID").String()
+		if id, ok := f["trip_id"]; ok && id != tripID {
+			return false
+		}
+		if i := s.findTrip(tripID); i >= 0 && s.isPast(s.db.Trips[i]) != past {
+			return false
+		}
+		return true
+	}
+
+	// Page through the objects of all the types as if they were one list.
+	all := &tripit.Response{}
+	s.appendObjects(all, match)
+	var n int
+	for _, typ := range objectTypes {
+		n += s.field(all, typ).Len()
+	}
+
+	resp := &tripit.Response{}
+	lo, hi, err := s.page(resp, f, n)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	i := 0
+	for _, typ := range objectTypes {
+		src, dst := s.field(all, typ), s.field(resp, typ)
+		for j := 0; j < src.Len(); j++ {
+			if i >= lo && i < hi {
+				dst.Set(reflect.Append(dst, src.Index(j)))
+			}
+			i++
+		}
+	}
+
+	return resp, http.StatusOK, nil
+}
+
+// get serves get/<type>/id/<id>.
+func (s *Server) get(typ tripit.Type, id string, f map[string]string) (*tripit.Response, int, error) {
+	resp := &tripit.Response{}
+
+	switch typ {
+	case tripit.TypeTrip:
+		i := s.findTrip(id)
+		if i < 0 {
+			return nil, http.StatusNotFound, fmt.Errorf("trip %s not found", id)
+		}
+		resp.Trips = tripit.Trips{s.db.Trips[i]}
+		if f["include_objects"] == "true" {
+			s.appendObjects(resp, func(typ tripit.Type, obj reflect.Value) bool {
+				return obj.FieldByName("TripID").String() == id
+			})
+		}
+	case tripit.TypeProfile:
+		for _, p := range s.db.Profiles {
+			if p.Attributes.Ref == id {
+				resp.Profiles = tripit.Profiles{p}
+			}
+		}
+		if len(resp.Profiles) < 1 {
+			return nil, http.StatusNotFound, fmt.Errorf("profile %s not found", id)
+		}
+	case tripit.TypePointsProgram:
+		for _, p := range s.db.PointsPrograms {
+			if strconv.FormatUint(uint64(p.ID), 10) == id {
+				resp.PointsPrograms = tripit.PointsPrograms{p}
+			}
+		}
+		if len(resp.PointsPrograms) < 1 {
+			return nil, http.StatusNotFound, fmt.Errorf("points program %s not found", id)
+		}
+	default:
+		if _, ok := objectFields[typ]; !ok {
+			return nil, http.StatusBadRequest, fmt.Errorf("unknown object type %q", typ)
+		}
+		objects := s.objects(typ)
+		i := findID(objects, id)
+		if i < 0 {
+			return nil, http.StatusNotFound, fmt.Errorf("%s %s not found", typ, id)
+		}
+		dst := s.field(resp, typ)
+		dst.Set(reflect.Append(dst, objects.Index(i)))
+	}
+
+	return resp, http.StatusOK, nil
+}
+
+// delete serves delete/<type>/id/<id>.
+func (s *Server) delete(typ tripit.Type, id string) (*tripit.Response, int, error) {
+	switch typ {
+	case tripit.TypeTrip:
+		i := s.findTrip(id)
+		if i < 0 {
+			return nil, http.StatusNotFound, fmt.Errorf("trip %s not found", id)
+		}
+		s.db.Trips = append(s.db.Trips[:i], s.db.Trips[i+1:]...)
+
+		// Deleting a trip deletes its objects.
+		for _, t := range objectTypes {
+			objects := s.objects(t)
+			kept := reflect.MakeSlice(objects.Type(), 0, objects.Len())
+			for j := 0; j < objects.Len(); j++ {
+				if objects.Index(j).FieldByName("TripID").String() != id {
+					kept = reflect.Append(kept, objects.Index(j))
+				}
+			}
+			objects.Set(kept)
+		}
+	case tripit.TypeSegment:
+		if !s.deleteSegment(id) {
+			return nil, http.StatusNotFound, fmt.Errorf("segment %s not found", id)
+		}
+	default:
+		if _, ok := objectFields[typ]; !ok {
+			return nil, http.StatusBadRequest, fmt.Errorf("unknown object type %q", typ)
+		}
+		objects := s.objects(typ)
+		i := findID(objects, id)
+		if i < 0 {
+			return nil, http.StatusNotFound, fmt.Errorf("%s %s not found", typ, id)
+		}
+		objects.Set(reflect.AppendSlice(objects.Slice(0, i), objects.Slice(i+1, objects.Len())))
+	}
+
+	return &tripit.Response{}, http.StatusOK, nil
+}
+
+// deleteSegment deletes the segment with the id from the object it is in.
+func (s *Server) deleteSegment(id string) bool {
+	for _, typ := range objectTypes {
+		objects := s.objects(typ)
+		for i := 0; i < objects.Len(); i++ {
+			segments := objects.Index(i).FieldByName("Segments")
+			if !segments.IsValid() {
+				continue
+			}
+			if j := findID(segments, id); j >= 0 {
+				segments.Set(reflect.AppendSlice(segments.Slice(0, j), segments.Slice(j+1, segments.Len())))
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// replace serves replace/<type>/id/<id>.
+func (s *Server) replace(typ tripit.Type, id string, format tripit.Format, body string) (*tripit.Response, int, error) {
+	req, err := decodeRequest(format, body)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	name, ok := requestFields[typ]
+	if !ok {
+		return nil, http.StatusBadRequest, fmt.Errorf("unknown object type %q", typ)
+	}
+	obj := reflect.ValueOf(req).FieldByName(name)
+	if obj.IsNil() {
+		return nil, http.StatusBadRequest, fmt.Errorf("request has no %s", name)
+	}
+	v := obj.Elem()
+	v.FieldByName("ID").SetString(id)
+
+	resp := &tripit.Response{}
+	if typ == tripit.TypeTrip {
+		i := s.findTrip(id)
+		if i < 0 {
+			return nil, http.StatusNotFound, fmt.Errorf("trip %s not found", id)
+		}
+		s.db.Trips[i] = v.Interface().(tripit.Trip)
+		resp.Trips = tripit.Trips{s.db.Trips[i]}
+		return resp, http.StatusOK, nil
+	}
+
+	objects := s.objects(typ)
+	i := findID(objects, id)
+	if i < 0 {
+		return nil, http.StatusNotFound, fmt.Errorf("%s %s not found", typ, id)
+	}
+	s.assignIDs(v)
+	objects.Index(i).Set(v)
+
+	dst := s.field(resp, typ)
+	dst.Set(reflect.Append(dst, v))
+	return resp, http.StatusOK, nil
+}
+
+// create serves create.
+func (s *Server) create(format tripit.Format, body string) (*tripit.Response, int, error) {
+	req, err := decodeRequest(format, body)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	resp := &tripit.Response{}
+	if req.Trip != nil {
+		trip := *req.Trip
+		trip.ID = s.newID()
+		s.db.Trips = append(s.db.Trips, trip)
+		resp.Trips = tripit.Trips{trip}
+	}
+
+	for typ, name := range requestFields {
+		if typ == tripit.TypeTrip {
+			continue
+		}
+		obj := reflect.ValueOf(req).FieldByName(name)
+		if obj.IsNil() {
+			continue
+		}
+
+		v := obj.Elem()
+		v.FieldByName("ID").SetString("")
+		if tripID := v.FieldByName("TripID"); tripID.String() == "" {
+			// TripIt creates a trip for objects without one.
+			trip := tripit.Trip{ID: s.newID(), DisplayName: "Trip"}
+			s.db.Trips = append(s.db.Trips, trip)
+			tripID.SetString(trip.ID)
+		} else if s.findTrip(tripID.String()) < 0 {
+			return nil, http.StatusNotFound, fmt.Errorf("trip %s not found", tripID.String())
+		}
+		// The user that creates an object is traveling on it.
+		if traveler := v.FieldByName("IsClientTraveler"); traveler.IsValid() {
+			traveler.SetBool(true)
+		}
+		s.assignIDs(v)
+
+		objects := s.objects(typ)
+		objects.Set(reflect.Append(objects, v))
+		dst := s.field(resp, typ)
+		dst.Set(reflect.Append(dst, v))
+	}
+
+	if len(req.Invitations) < 1 && reflect.ValueOf(*resp).IsZero() {
+		return nil, http.StatusBadRequest, fmt.Errorf("request has no objects")
+	}
+
+	return resp, http.StatusOK, nil
+}
+
+// page sets the paging fields of resp for n results and returns the bounds
+// of the page.
+func (s *Server) page(resp *tripit.Response, f map[string]string, n int) (int, int, error) {
+	num, size := 1, s.PageSize
+	if v, ok := f["page_num"]; ok {
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 1 {
+			return 0, 0, fmt.Errorf("invalid page_num %q", v)
+		}
+		num = i
+	}
+	if v, ok := f["page_size"]; ok {
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 1 {
+			return 0, 0, fmt.Errorf("invalid page_size %q", v)
+		}
+		size = i
+	}
+	if size < 1 {
+		size = DefaultPageSize
+	}
+
+	maxPage := (n + size - 1) / size
+	if maxPage < 1 {
+		maxPage = 1
+	}
+
+	resp.PageNum = strconv.Itoa(num)
+	resp.PageSize = strconv.Itoa(size)
+	resp.MaxPage = strconv.Itoa(maxPage)
+
+	lo, hi := (num-1)*size, num*size
+	if lo > n {
+		lo = n
+	}
+	if hi > n {
+		hi = n
+	}
+	return lo, hi, nil
+}
+
+// appendObjects appends the objects that match to resp.
+func (s *Server) appendObjects(resp *tripit.Response, match func(tripit.Type, reflect.Value) bool) {
+	for _, typ := range objectTypes {
+		src, dst := s.objects(typ), s.field(resp, typ)
+		for i := 0; i < src.Len(); i++ {
+			if match(typ, src.Index(i)) {
+				dst.Set(reflect.Append(dst, src.Index(i)))
+			}
+		}
+	}
+}
+
+// inviteeProfiles returns the profiles of the invitees of the trips.
+func (s *Server) inviteeProfiles(trips []tripit.Trip) tripit.Profiles {
+	var profiles tripit.Profiles
+	seen := map[string]bool{}
+	for _, t := range trips {
+		for _, invitee := range t.Invitees {
+			ref := invitee.Attributes.ProfileRef
+			if seen[ref] {
+				continue
+			}
+			for _, p := range s.db.Profiles {
+				if p.Attributes.Ref == ref {
+					seen[ref] = true
+					profiles = append(profiles, p)
+				}
+			}
+		}
+	}
+	return profiles
+}
+
+// isPast returns if the trip ended before today.
+func (s *Server) isPast(trip tripit.Trip) bool {
+	return trip.EndDate != "" && trip.EndDate < s.Now().Format("2006-01-02")
+}
+
+func (s *Server) findTrip(id string) int {
+	for i, t := range s.db.Trips {
+		if t.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// objects returns the slice of the objects of the type on the server.
+func (s *Server) objects(typ tripit.Type) reflect.Value {
+	return s.field(&s.db, typ)
+}
+
+func (s *Server) field(resp *tripit.Response, typ tripit.Type) reflect.Value {
+	return reflect.ValueOf(resp).Elem().FieldByName(objectFields[typ])
+}
+
+// assignIDs sets the IDs of the object and its segments that have none.
+func (s *Server) assignIDs(v reflect.Value) {
+	if id := v.FieldByName("ID"); id.String() == "" {
+		id.SetString(s.newID())
+	}
+
+	segments := v.FieldByName("Segments")
+	if !segments.IsValid() {
+		return
+	}
+	// Copy the segments so the caller's slice is not changed.
+	segments.Set(reflect.AppendSlice(reflect.MakeSlice(segments.Type(), 0, segments.Len()), segments))
+	for i := 0; i < segments.Len(); i++ {
+		if id := segments.Index(i).FieldByName("ID"); id.String() == "" {
+			id.SetString(s.newID())
+		}
+	}
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return strconv.Itoa(s.nextID)
+}
+
+// write writes the response in the format.
+func (s *Server) write(w http.ResponseWriter, format tripit.Format, statusCode int, resp *tripit.Response) {
+	resp.Timestamp = strconv.FormatInt(s.Now().Unix(), 10)
+
+	var (
+		b   []byte
+		err error
+	)
+	if format == tripit.FormatXML {
+		w.Header().Set("Content-Type", "application/xml")
+		b, err = xml.Marshal(resp)
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+		b, err = json.Marshal(resp)
+		// The TripIt JSON has the attributes of the XML in "@attributes".
+		b = bytes.Replace(b, []byte(`"_attributes"`), []byte(`"@attributes"`), -1)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(statusCode)
+	w.Write(b)
+}
+
+// writeError writes a response with the error and the status code.
+func (s *Server) writeError(w http.ResponseWriter, format tripit.Format, statusCode int, description string) {
+	s.write(w, format, statusCode, &tripit.Response{
+		Errors: tripit.Errors{newError(statusCode, description, s.Now())},
+	})
+}
+
+func newError(code int, description string, now time.Time) tripit.Error {
+	return tripit.Error{
+		Code:              code,
+		DetailedErrorCode: float64(code) + 0.1,
+		Description:       description,
+		EntityType:        "Response",
+		Timestamp:         now.UTC().Format(time.RFC3339),
+	}
+}
+
+// decodeRequest decodes the body of a replace or create.
+func decodeRequest(format tripit.Format, body string) (tripit.Request, error) {
+	var req tripit.Request
+	if body == "" {
+		return req, fmt.Errorf("request has no %s parameter", format)
+	}
+
+	var err error
+	if format == tripit.FormatXML {
+		err = xml.Unmarshal([]byte(body), &req)
+	} else {
+		err = json.Unmarshal([]byte(strings.Replace(body, `"@attributes"`, `"_attributes"`, -1)), &req)
+	}
+	if err != nil {
+		return req, fmt.Errorf("decoding %s request failed: %v", format, err)
+	}
+	return req, nil
+}
+
+// filters returns the filters in the path segments, which are name/value
+// pairs.
+func filters(parts []string) map[string]string {
+	f := map[string]string{}
+	for i := 0; i+1 < len(parts); i += 2 {
+		f[parts[i]] = parts[i+1]
+	}
+	return f
+}
+
+// findID returns the index of the element of the slice with the id.
+func findID(objects reflect.Value, id string) int {
+	for i := 0; i < objects.Len(); i++ {
+		if objects.Index(i).FieldByName("ID").String() == id {
+			return i
+		}
+	}
+	return -1
+}
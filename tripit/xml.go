@@ -0,0 +1,138 @@
+package tripit
+
+import (
+	"encoding/xml"
+	"reflect"
+)
+
+// The XML representation of the TripIt API uses attributes where the JSON
+// representation uses "@attributes" objects, these methods map between them.
+
+// UnmarshalXMLAttr sets the profile reference from the ref attribute.
+func (a *ProfileAttributes) UnmarshalXMLAttr(attr xml.Attr) error {
+	a.Ref = attr.Value
+	return nil
+}
+
+// MarshalXMLAttr returns the ref attribute for the profile reference.
+func (a ProfileAttributes) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if a.Ref == "" {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: a.Ref}, nil
+}
+
+// UnmarshalXMLAttr sets the profile reference from the profile_ref attribute.
+func (a *ClosenessMatchAttributes) UnmarshalXMLAttr(attr xml.Attr) error {
+	a.ProfileRef = attr.Value
+	return nil
+}
+
+// MarshalXMLAttr returns the profile_ref attribute for the profile reference.
+func (a ClosenessMatchAttributes) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if a.ProfileRef == "" {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: a.ProfileRef}, nil
+}
+
+// UnmarshalXMLAttr sets the profile reference from the profile_ref attribute.
+func (a *InviteeAttributes) UnmarshalXMLAttr(attr xml.Attr) error {
+	a.ProfileRef = attr.Value
+	return nil
+}
+
+// MarshalXMLAttr returns the profile_ref attribute for the profile reference.
+func (a InviteeAttributes) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if a.ProfileRef == "" {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: a.ProfileRef}, nil
+}
+
+// The encoding/xml package does not omit empty structs, so the structs that
+// are embedded in objects we send omit themselves when they are empty.
+
+// MarshalXML encodes the DateTime, omitting it if it is empty.
+func (d DateTime) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type dateTime DateTime
+	return encodeUnlessZero(e, start, dateTime(d))
+}
+
+// MarshalXML encodes the Address, omitting it if it is empty.
+func (a Address) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type address Address
+	return encodeUnlessZero(e, start, address(a))
+}
+
+// MarshalXML encodes the FlightStatus, omitting it if it is empty.
+func (s FlightStatus) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type flightStatus FlightStatus
+	return encodeUnlessZero(e, start, flightStatus(s))
+}
+
+// MarshalXML encodes the Traveler, omitting it if it is empty.
+func (t Traveler) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type traveler Traveler
+	return encodeUnlessZero(e, start, traveler(t))
+}
+
+// MarshalXML encodes the ClosenessMatches, omitting them if they are empty.
+func (c ClosenessMatches) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type closenessMatches ClosenessMatches
+	return encodeUnlessZero(e, start, closenessMatches(c))
+}
+
+// MarshalXML encodes the Remarks, omitting them if they are empty.
+func (r Remarks) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type remarks Remarks
+	return encodeUnlessZero(e, start, remarks(r))
+}
+
+// MarshalXML encodes the TripShare, omitting it if it is empty.
+func (t TripShare) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type tripShare TripShare
+	return encodeUnlessZero(e, start, tripShare(t))
+}
+
+// The encoding/xml package writes the parent of an "a>b" path even when the
+// slice is empty, only a nil pointer leaves it out, so the objects with
+// those paths encode the slices through pointers that are nil when empty.
+
+// MarshalXML encodes the Trip, omitting the TripInvitees if there are none.
+func (t Trip) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type trip Trip
+	v := struct {
+		trip
+		Invitees *Invitees `xml:"TripInvitees>Invitee,omitempty"`
+	}{trip: trip(t)}
+	if len(t.Invitees) > 0 {
+		v.Invitees = &t.Invitees
+	}
+	return e.EncodeElement(v, start)
+}
+
+// MarshalXML encodes the Profile, omitting the ProfileEmailAddresses and
+// GroupMemberships if there are none.
+func (p Profile) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type profile Profile
+	v := struct {
+		profile
+		ProfileEmailAddresses *ProfileEmailAddresses `xml:"ProfileEmailAddresses>ProfileEmailAddress,omitempty"`
+		GroupMemberships      *GroupMemberships      `xml:"GroupMemberships>Group,omitempty"`
+	}{profile: profile(p)}
+	if len(p.ProfileEmailAddresses) > 0 {
+		v.ProfileEmailAddresses = &p.ProfileEmailAddresses
+	}
+	if len(p.GroupMemberships) > 0 {
+		v.GroupMemberships = &p.GroupMemberships
+	}
+	return e.EncodeElement(v, start)
+}
+
+func encodeUnlessZero(e *xml.Encoder, start xml.StartElement, v interface{}) error {
+	if reflect.ValueOf(v).IsZero() {
+		return nil
+	}
+	return e.EncodeElement(v, start)
+}
@@ -0,0 +1,323 @@
+package tripit
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+var (
+	testDateTime = DateTime{Date: "2024-03-15", Time: "09:30:00", Timezone: "America/Los_Angeles", UTCOffset: "-07:00"}
+	testAddress  = Address{Address: "San Francisco, CA", City: "San Francisco", Country: "US", Latitude: 37.618999, Longitude: -122.375}
+	testTraveler = Traveler{FirstName: "Jess", LastName: "Frazelle", FrequentTravelerNum: "123", TicketNum: "0061234567890"}
+	testImage    = Image{Caption: "SFO", URL: "https://example.com/sfo.png"}
+)
+
+func TestResponseXMLRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		resp Response
+	}{
+		{
+			name: "error",
+			resp: Response{Errors: []Error{{Code: 401, DetailedErrorCode: 106.1, Description: "Unauthorized", EntityType: "Trip", Timestamp: "2024-03-15T09:30:00-07:00"}}},
+		},
+		{
+			name: "warning",
+			resp: Response{Warnings: []Warning{{Description: "Object not found", EntityType: "Trip", Timestamp: "2024-03-15T09:30:00-07:00"}}},
+		},
+		{
+			name: "activity",
+			resp: Response{Activities: Activities{{
+				ID: "1", TripID: "2", IsClientTraveler: true, DisplayName: "Concert", Images: []Image{testImage},
+				StartDateTime: testDateTime, EndTime: "22:00:00", Address: testAddress, Participants: Travelers{testTraveler},
+				DetailTypeCode: ActivityDetailTypeConcert, LocationName: "The Fillmore",
+			}}},
+		},
+		{
+			name: "car",
+			resp: Response{Cars: Cars{{
+				ID: "1", TripID: "2", DisplayName: "Rental car", CancellationDateTime: testDateTime, IsPurchased: true,
+				StartDateTime: testDateTime, EndDateTime: testDateTime, StartLocationAddress: testAddress, EndLocationAddress: testAddress,
+				Drivers: Travelers{testTraveler}, StartLocationName: "SFO", CarType: "Compact",
+			}}},
+		},
+		{
+			name: "cruise",
+			resp: Response{Cruises: Cruises{{
+				ID: "1", TripID: "2", DisplayName: "Cruise", ShipName: "Boaty McBoatface", Travelers: Travelers{testTraveler},
+				Segments: []CruiseSegment{
+					{ID: "3", StartDateTime: testDateTime, LocationAddress: testAddress, LocationName: "Juneau", DetailTypeCode: CruiseDetailTypePortOfCall},
+					{ID: "4", EndDateTime: testDateTime, LocationName: "Skagway"},
+				},
+			}}},
+		},
+		{
+			name: "directions",
+			resp: Response{Directions: Directions{{ID: "1", TripID: "2", DisplayName: "Directions", DateTime: testDateTime, StartAddress: testAddress, EndAddress: testAddress}}},
+		},
+		{
+			name: "flight",
+			resp: Response{Flights: Flights{{
+				ID: "1", TripID: "2", IsClientTraveler: true, DisplayName: "SFO to JFK", RecordLocator: "ABC123",
+				Travelers: Travelers{testTraveler, {FirstName: "Ada", LastName: "Lovelace"}},
+				Segments: FlightSegments{
+					{
+						ID: "3", StartDateTime: testDateTime, EndDateTime: testDateTime,
+						StartAirportCode: "SFO", StartAirportLatitude: 37.618999, StartAirportLongitude: -122.375, StartCityName: "San Francisco",
+						EndAirportCode: "JFK", EndCityName: "New York", MarketingAirline: "United", MarketingAirlineCode: "UA", MarketingFlightNumber: "1234",
+						IsHidden: true,
+						Status: FlightStatus{
+							ScheduledDepartureDateTime: testDateTime, EstimatedArrivalDateTime: testDateTime,
+							FlightStatus: FlightStatusDelayed, IsConnectionAtRisk: true, DepartureGate: "A1",
+						},
+					},
+					{ID: "4", StartAirportCode: "JFK", EndAirportCode: "BOS"},
+				},
+			}}},
+		},
+		{
+			name: "lodging",
+			resp: Response{Lodging: Lodges{{ID: "1", TripID: "2", DisplayName: "Hotel", StartDateTime: testDateTime, EndDateTime: testDateTime, Address: testAddress, Guests: Travelers{testTraveler}, NumberRooms: "1"}}},
+		},
+		{
+			name: "map",
+			resp: Response{Maps: Maps{{ID: "1", TripID: "2", DisplayName: "Map", DateTime: testDateTime, Address: testAddress}}},
+		},
+		{
+			name: "note",
+			resp: Response{Notes: Notes{{ID: "1", TripID: "2", DisplayName: "Note", DateTime: testDateTime, DetailTypeCode: NoteDetailTypeArticle, Text: "Bring a coat", URL: "https://example.com"}}},
+		},
+		{
+			name: "rail",
+			resp: Response{Rails: Rails{{
+				ID: "1", TripID: "2", DisplayName: "Train", Travelers: Travelers{testTraveler},
+				Segments: RailSegments{{ID: "3", StartDateTime: testDateTime, EndDateTime: testDateTime, StartStationAddress: testAddress, StartStationName: "Penn Station", EndStationName: "Union Station", TrainNumber: "2151"}},
+			}}},
+		},
+		{
+			name: "restaurant",
+			resp: Response{Restaurants: Restaurants{{ID: "1", TripID: "2", DisplayName: "Dinner", DateTime: testDateTime, Address: testAddress, ReservationHolder: testTraveler, Cuisine: "Italian", NumberPatrons: "2"}}},
+		},
+		{
+			name: "transport",
+			resp: Response{Transports: Transports{{
+				ID: "1", TripID: "2", DisplayName: "Ferry", Travelers: Travelers{testTraveler},
+				Segments: TransportSegments{{ID: "3", StartDateTime: testDateTime, StartLocationAddress: testAddress, StartLocationName: "Ferry Building", DetailTypeCode: TransportDetailTypeFerry, NumberPassengers: "2"}},
+			}}},
+		},
+		{
+			name: "trip",
+			resp: Response{Trips: Trips{{
+				ID: "2", DisplayName: "New York", StartDate: "2024-03-15", EndDate: "2024-03-20", IsPrivate: true,
+				PrimaryLocation: "New York, NY", PrimaryLocationAddress: testAddress,
+				ClosenessMatches: ClosenessMatches{ClosenessMatches: []ClosenessMatch{{Attributes: ClosenessMatchAttributes{ProfileRef: "near"}}}},
+				Invitees: Invitees{
+					{IsTraveler: true, Attributes: InviteeAttributes{ProfileRef: "traveler"}},
+					{IsReadOnly: true, Attributes: InviteeAttributes{ProfileRef: "viewer"}},
+				},
+				Remarks: Remarks{Remarks: []Remark{{RecordLocator: "ABC123", Notes: "Window seat"}}},
+			}}},
+		},
+		{
+			name: "weather",
+			resp: Response{Weather: WeatherReports{{ID: "1", TripID: "2", Date: "2024-03-15", Location: "New York, NY", AvgHighTempC: 12.5, AvgLowTempC: -1.5}}},
+		},
+		{
+			name: "points program",
+			resp: Response{PointsPrograms: []PointsProgram{{
+				ID: 1, Name: "MileagePlus", AccountNumber: "123", Balance: "1000", TotalNumActivities: 1, TotalNumExpirations: 1,
+				Activities:  []PointsProgramActivity{{Date: "2024-03-15", Description: "SFO to JFK", Total: "2500"}},
+				Expirations: []PointsProgramExpiration{{Date: "2025-03-15", Amount: "1000"}},
+			}}},
+		},
+		{
+			name: "profile",
+			resp: Response{Profiles: Profiles{{
+				Attributes:            ProfileAttributes{Ref: "abc"},
+				ProfileEmailAddresses: ProfileEmailAddresses{{EmailRef: "e1", Address: "traveler@example.com", IsConfirmed: true, IsPrimary: true}},
+				GroupMemberships:      GroupMemberships{{DisplayName: "Travelers", URL: "https://example.com/group"}},
+				IsClient:              true, ScreenName: "traveler", PublicDisplayName: "Traveler",
+			}}},
+		},
+		{
+			name: "paging",
+			resp: Response{Timestamp: "1710520200", NumBytes: 100, PageNum: "1", PageSize: "5", MaxPage: "2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := xml.Marshal(tt.resp)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got Response
+			if err := xml.Unmarshal(b, &got); err != nil {
+				t.Fatalf("unmarshaling %s failed: %v", b, err)
+			}
+			got.XMLName = xml.Name{}
+			if !reflect.DeepEqual(got, tt.resp) {
+				t.Errorf("round trip of %s\ngot:  %+v\nwant: %+v", b, got, tt.resp)
+			}
+		})
+	}
+}
+
+func TestRequestXMLRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+	}{
+		{
+			name: "invitation",
+			req: Request{Invitations: []Invitation{{
+				EmailAddresses: EmailAddresses{Addresses: []string{"a@example.com", "b@example.com"}},
+				TripShare:      &TripShare{TripID: 2, IsTraveler: true},
+				Message:        "Join me",
+			}}},
+		},
+		{name: "trip", req: Request{Trip: &Trip{DisplayName: "New York", StartDate: "2024-03-15", EndDate: "2024-03-20"}}},
+		{name: "activity", req: Request{Activity: &Activity{TripID: "2", DisplayName: "Concert", StartDateTime: testDateTime}}},
+		{name: "car", req: Request{Car: &Car{TripID: "2", DisplayName: "Rental car", Drivers: Travelers{testTraveler}}}},
+		{name: "cruise", req: Request{Cruise: &Cruise{TripID: "2", Segments: []CruiseSegment{{LocationName: "Juneau"}}}}},
+		{name: "directions", req: Request{Directions: &Direction{TripID: "2", StartAddress: testAddress}}},
+		{name: "flight", req: Request{Flight: &Flight{TripID: "2", Segments: FlightSegments{{StartAirportCode: "SFO", EndAirportCode: "JFK", StartDateTime: testDateTime}}}}},
+		{name: "lodging", req: Request{Lodging: &Lodging{TripID: "2", Address: testAddress}}},
+		{name: "map", req: Request{Map: &Map{TripID: "2", Address: testAddress}}},
+		{name: "note", req: Request{Note: &Note{TripID: "2", Text: "Bring a coat"}}},
+		{name: "rail", req: Request{Rail: &Rail{TripID: "2", Segments: RailSegments{{TrainNumber: "2151"}}}}},
+		{name: "restaurant", req: Request{Restaurant: &Restaurant{TripID: "2", ReservationHolder: testTraveler}}},
+		{name: "transport", req: Request{Transport: &Transport{TripID: "2", Segments: TransportSegments{{CarrierName: "Ferry"}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := xml.Marshal(tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got Request
+			if err := xml.Unmarshal(b, &got); err != nil {
+				t.Fatalf("unmarshaling %s failed: %v", b, err)
+			}
+			got.XMLName = xml.Name{}
+			if !reflect.DeepEqual(got, tt.req) {
+				t.Errorf("round trip of %s\ngot:  %+v\nwant: %+v", b, got, tt.req)
+			}
+		})
+	}
+}
+
+func TestXMLOmitsEmpty(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want string
+	}{
+		{
+			name: "flight",
+			v:    Request{Flight: &Flight{DisplayName: "SFO to JFK", Segments: FlightSegments{{StartAirportCode: "SFO"}}}},
+			want: `<Request><AirObject><display_name>SFO to JFK</display_name><Segment><start_airport_code>SFO</start_airport_code></Segment></AirObject></Request>`,
+		},
+		{
+			name: "new trip",
+			v:    Request{Trip: &Trip{DisplayName: "New York"}},
+			want: `<Request><Trip><display_name>New York</display_name></Trip></Request>`,
+		},
+		{
+			name: "restaurant",
+			v:    Request{Restaurant: &Restaurant{DisplayName: "Dinner"}},
+			want: `<Request><RestaurantObject><display_name>Dinner</display_name></RestaurantObject></Request>`,
+		},
+		{
+			name: "invitation",
+			v:    Request{Invitations: []Invitation{{EmailAddresses: EmailAddresses{Addresses: []string{"a@example.com"}}, TripShare: &TripShare{}}}},
+			want: `<Request><Invitation><EmailAddresses><address>a@example.com</address></EmailAddresses></Invitation></Request>`,
+		},
+		{
+			name: "profile",
+			v:    Response{Profiles: Profiles{{ScreenName: "traveler"}}},
+			want: `<Response><Profile><screen_name>traveler</screen_name></Profile></Response>`,
+		},
+		{
+			name: "trip",
+			v: Response{Trips: Trips{{
+				ID:               "2",
+				ClosenessMatches: ClosenessMatches{ClosenessMatches: []ClosenessMatch{{}}},
+				Invitees:         Invitees{{IsTraveler: true}},
+			}}},
+			want: `<Response><Trip><id>2</id><ClosenessMatches><Match></Match></ClosenessMatches><TripInvitees><Invitee><is_traveler>true</is_traveler></Invitee></TripInvitees></Trip></Response>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := xml.Marshal(tt.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("got:  %s\nwant: %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestXMLAttributes(t *testing.T) {
+	const body = `<Response>
+	<Trip>
+		<id>2</id>
+		<ClosenessMatches><Match profile_ref="near"/></ClosenessMatches>
+		<TripInvitees>
+			<Invitee profile_ref="traveler"><is_traveler>true</is_traveler></Invitee>
+			<Invitee profile_ref="viewer"><is_read_only>true</is_read_only></Invitee>
+		</TripInvitees>
+	</Trip>
+	<Profile ref="abc"><screen_name>traveler</screen_name></Profile>
+</Response>`
+
+	// The JSON the API sends for the same response.
+	const jsonBody = `{
+	"Trip": {
+		"id": "2",
+		"ClosenessMatches": {"Match": [{"@attributes": {"profile_ref": "near"}}]},
+		"TripInvitees": [
+			{"@attributes": {"profile_ref": "traveler"}, "is_traveler": "true"},
+			{"@attributes": {"profile_ref": "viewer"}, "is_read_only": "true"}
+		]
+	},
+	"Profile": {"@attributes": {"ref": "abc"}, "screen_name": "traveler"}
+}`
+
+	want := Response{
+		Trips: Trips{{
+			ID:               "2",
+			ClosenessMatches: ClosenessMatches{ClosenessMatches: []ClosenessMatch{{Attributes: ClosenessMatchAttributes{ProfileRef: "near"}}}},
+			Invitees: Invitees{
+				{IsTraveler: true, Attributes: InviteeAttributes{ProfileRef: "traveler"}},
+				{IsReadOnly: true, Attributes: InviteeAttributes{ProfileRef: "viewer"}},
+			},
+		}},
+		Profiles: Profiles{{Attributes: ProfileAttributes{Ref: "abc"}, ScreenName: "traveler"}},
+	}
+
+	var got Response
+	if err := (&Client{format: FormatXML}).decode([]byte(body), &got); err != nil {
+		t.Fatal(err)
+	}
+	got.XMLName = xml.Name{}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("xml\ngot:  %+v\nwant: %+v", got, want)
+	}
+
+	var gotJSON Response
+	if err := (&Client{format: FormatJSON}).decode([]byte(jsonBody), &gotJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotJSON, want) {
+		t.Errorf("json\ngot:  %+v\nwant: %+v", gotJSON, want)
+	}
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	calendar "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// defaultWriteConcurrency is how many writes to a calendar are in flight
+	// at the same time if the account does not say.
+	defaultWriteConcurrency = 4
+	// maxWriteConcurrency is the most writes in flight the config allows,
+	// more only run into the quota of the calendar API.
+	maxWriteConcurrency = 16
+
+	// maxWriteAttempts is how many times a write is tried when the calendar
+	// API throttles it or fails with a server error.
+	maxWriteAttempts = 5
+	// serverErrorBackoff is how long the writer waits to try a write again
+	// after the first server error, it doubles with every one after that.
+	serverErrorBackoff = time.Second
+
+	// The limiter starts at and never goes faster than minWriteInterval
+	// between writes, about the default quota of the calendar API per
+	// user, and slows down to at most maxWriteInterval when throttled.
+	minWriteInterval = 100 * time.Millisecond
+	maxWriteInterval = 10 * time.Second
+
+	// maxWriteErrors is how many of the errors of the failed writes are in
+	// the error of the sync, the rest are only logged.
+	maxWriteErrors = 3
+)
+
+const (
+	writeInsert = "insert"
+	writePatch  = "patch"
+	writeDelete = "delete"
+)
+
+// calendarWrite is a write to a calendar.
+type calendarWrite struct {
+	op string
+	// id is the ID of the event to patch or delete.
+	id    string
+	title string
+	// event is the event to insert, or the patch.
+	event *calendar.Event
+}
+
+func (w calendarWrite) String() string {
+	switch w.op {
+	case writeInsert:
+		return fmt.Sprintf("inserting %q", w.title)
+	case writePatch:
+		return fmt.Sprintf("patching %q (%s)", w.title, w.id)
+	}
+	return fmt.Sprintf("deleting %q (%s)", w.title, w.id)
+}
+
+// calendarWriter does the writes to the calendars of an account with a
+// bounded number of workers.
+type calendarWriter struct {
+	client      *calendar.Service
+	concurrency int
+	limiter     *adaptiveLimiter
+	// backoff is the wait after the first server error of a write.
+	backoff time.Duration
+}
+
+func newCalendarWriter(client *calendar.Service, concurrency int) *calendarWriter {
+	if concurrency < 1 {
+		concurrency = defaultWriteConcurrency
+	}
+	return &calendarWriter{
+		client:      client,
+		concurrency: concurrency,
+		limiter:     newAdaptiveLimiter(minWriteInterval, maxWriteInterval),
+		backoff:     serverErrorBackoff,
+	}
+}
+
+// write does the writes to the calendar and returns the stats and the
+// error of the ones that failed, one failed write does not stop the
+// others. No new writes are started once ctx is done, the ones in progress
+// run until writeCtx is done.
+func (cw *calendarWriter) write(ctx, writeCtx context.Context, calendarName string, writes []calendarWrite) (syncStats, error) {
+	var (
+		mu    sync.Mutex
+		stats syncStats
+		errs  []error
+		wg    sync.WaitGroup
+	)
+
+	queue := make(chan calendarWrite)
+	workers := cw.concurrency
+	if workers > len(writes) {
+		workers = len(writes)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for w := range queue {
+				err := cw.do(writeCtx, calendarName, w)
+
+				mu.Lock()
+				if err != nil {
+					logrus.Errorf("%s in google calendar %s failed: %v", w, calendarName, err)
+					stats.Failed++
+					errs = append(errs, fmt.Errorf("%s failed: %v", w, err))
+				} else {
+					switch w.op {
+					case writeInsert:
+						stats.Created++
+					case writePatch:
+						stats.Updated++
+					case writeDelete:
+						stats.Deleted++
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var interrupted bool
+feed:
+	for _, w := range writes {
+		// Check first, select picks at random when both are ready.
+		if ctx.Err() != nil {
+			interrupted = true
+			break
+		}
+		select {
+		case queue <- w:
+		case <-ctx.Done():
+			interrupted = true
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if interrupted {
+		return stats, fmt.Errorf("sync to google calendar %s was interrupted after %d writes", calendarName, stats.Created+stats.Updated+stats.Deleted+stats.Failed)
+	}
+	return stats, writesError(calendarName, errs)
+}
+
+// do does the write, it is tried again when the calendar API throttles it
+// or fails with a server error, after a backoff for the server errors.
+func (cw *calendarWriter) do(ctx context.Context, calendarName string, w calendarWrite) error {
+	var (
+		err error
+		// maybeInserted is set once an insert failed with a server error,
+		// it may have been done anyway.
+		maybeInserted bool
+	)
+	for attempt := 1; attempt <= maxWriteAttempts; attempt++ {
+		if err := cw.limiter.wait(ctx); err != nil {
+			return err
+		}
+
+		switch w.op {
+		case writeInsert:
+			err = cw.insert(ctx, calendarName, w, maybeInserted)
+		case writePatch:
+			_, err = cw.client.Events.Patch(calendarName, w.id, w.event).Context(ctx).Do()
+		case writeDelete:
+			err = cw.client.Events.Delete(calendarName, w.id).Context(ctx).Do()
+			// The event is gone already, deleted by the user or by an
+			// earlier attempt whose response was lost.
+			if isGone(err) {
+				logrus.Debugf("%s in google calendar %s: the event is already deleted", w, calendarName)
+				err = nil
+			}
+		default:
+			return fmt.Errorf("unknown write %q", w.op)
+		}
+
+		switch {
+		case err == nil:
+			cw.limiter.success()
+			return nil
+		case isThrottled(err):
+			cw.limiter.throttled()
+		case isServerError(err):
+			maybeInserted = w.op == writeInsert
+			// The server is not asking us to slow down, but give it time to
+			// recover before trying again.
+			if attempt < maxWriteAttempts {
+				if err := sleep(ctx, cw.backoff<<uint(attempt-1)); err != nil {
+					return err
+				}
+			}
+		default:
+			return err
+		}
+	}
+	return fmt.Errorf("giving up after %d attempts: %v", maxWriteAttempts, err)
+}
+
+// insert inserts the event of the write. If an earlier attempt may have
+// inserted it, it is looked up by its key first, so it is not duplicated.
+func (cw *calendarWriter) insert(ctx context.Context, calendarName string, w calendarWrite, maybeInserted bool) error {
+	if maybeInserted {
+		found, err := cw.inserted(ctx, calendarName, w.event)
+		if err != nil {
+			return err
+		}
+		if found {
+			logrus.Debugf("%s in google calendar %s was done by an attempt that failed", w, calendarName)
+			return nil
+		}
+	}
+
+	_, err := cw.client.Events.Insert(calendarName, w.event).Context(ctx).Do()
+	return err
+}
+
+// inserted returns if the event was inserted in the calendar, looking it up
+// by its key.
+func (cw *calendarWriter) inserted(ctx context.Context, calendarName string, event *calendar.Event) (bool, error) {
+	key, owner := eventKey(event), eventOwner(event)
+	if key == "" {
+		return false, nil
+	}
+
+	events, err := cw.client.Events.List(calendarName).ShowDeleted(false).PrivateExtendedProperty(eventKeyProperty+"="+key, eventOwnerProperty+"="+owner).MaxResults(1).Context(ctx).Do()
+	if err != nil {
+		return false, err
+	}
+	return len(events.Items) > 0, nil
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// writesError returns the error for the failed writes to the calendar, or
+// nil if there are none.
+func writesError(calendarName string, errs []error) error {
+	if len(errs) < 1 {
+		return nil
+	}
+
+	var msgs []string
+	for i, err := range errs {
+		if i == maxWriteErrors {
+			msgs = append(msgs, fmt.Sprintf("and %d more", len(errs)-maxWriteErrors))
+			break
+		}
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Errorf("%d writes to google calendar %s failed: %s", len(errs), calendarName, strings.Join(msgs, "; "))
+}
+
+// isThrottled returns if the calendar API rejected the request because of
+// its quota.
+func isThrottled(err error) bool {
+	apiErr, ok := err.(*googleapi.Error)
+	if !ok {
+		return false
+	}
+	if apiErr.Code == http.StatusTooManyRequests {
+		return true
+	}
+	if apiErr.Code != http.StatusForbidden {
+		return false
+	}
+	for _, e := range apiErr.Errors {
+		switch e.Reason {
+		case "rateLimitExceeded", "userRateLimitExceeded":
+			return true
+		}
+	}
+	return false
+}
+
+// isGone returns if the calendar API says the event does not exist or was
+// deleted.
+func isGone(err error) bool {
+	apiErr, ok := err.(*googleapi.Error)
+	return ok && (apiErr.Code == http.StatusNotFound || apiErr.Code == http.StatusGone)
+}
+
+func isServerError(err error) bool {
+	apiErr, ok := err.(*googleapi.Error)
+	return ok && apiErr.Code >= http.StatusInternalServerError
+}
+
+// adaptiveLimiter spaces out requests, it slows down when the requests are
+// throttled and speeds back up as they succeed.
+type adaptiveLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	min, max time.Duration
+	next     time.Time
+	// slowed is when the limiter last slowed down, the requests that were
+	// already in flight then do not slow it down again.
+	slowed time.Time
+}
+
+func newAdaptiveLimiter(min, max time.Duration) *adaptiveLimiter {
+	return &adaptiveLimiter{
+		interval: min,
+		min:      min,
+		max:      max,
+	}
+}
+
+// wait waits for the turn of the request.
+func (l *adaptiveLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	if d := time.Until(at); d > 0 {
+		return sleep(ctx, d)
+	}
+	return ctx.Err()
+}
+
+// success speeds the limiter up by a fifth, up to its fastest.
+func (l *adaptiveLimiter) success() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.interval -= l.interval / 5
+	if l.interval < l.min {
+		l.interval = l.min
+	}
+}
+
+// throttled halves the speed of the limiter, down to its slowest, and
+// holds off the next request for the new interval.
+func (l *adaptiveLimiter) throttled() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.slowed) >= l.interval {
+		l.slowed = now
+		l.interval *= 2
+		if l.interval > l.max {
+			l.interval = l.max
+		}
+	}
+	if next := now.Add(l.interval); next.After(l.next) {
+		l.next = next
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jessfraz/tripitcalb0t/calendartest"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+func TestWriteInsertRetryIsIdempotent(t *testing.T) {
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	ctx := context.Background()
+	target := testTarget(t, calendarTarget{Calendar: testCalendar})
+	trips := testFlights(1)
+
+	// The insert is done, but the response is lost.
+	cs.FailAfter("insert", http.StatusServiceUnavailable, 1)
+	stats, err := syncEvents(ctx, ctx, testWriter(cs), target, nil, trips, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Created != 1 || stats.Failed != 0 {
+		t.Errorf("stats are %+v, want 1 created", stats)
+	}
+	if n := len(cs.Events(testCalendar)); n != 1 {
+		t.Errorf("calendar has %d events after the insert was retried, want 1", n)
+	}
+	if n := countRequests(cs, "insert"); n != 1 {
+		t.Errorf("sent %d inserts, want 1", n)
+	}
+}
+
+func TestWriteInsertRetry(t *testing.T) {
+	// The inserts are not done, so they have to be tried again.
+	for _, statusCode := range []int{http.StatusInternalServerError, http.StatusTooManyRequests} {
+		t.Run(http.StatusText(statusCode), func(t *testing.T) {
+			cs := calendartest.NewServer()
+			defer cs.Close()
+
+			ctx := context.Background()
+			target := testTarget(t, calendarTarget{Calendar: testCalendar})
+			trips := testFlights(1)
+
+			cs.Fail("insert", statusCode, 1)
+			stats, err := syncEvents(ctx, ctx, testWriter(cs), target, nil, trips, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.Created != 1 {
+				t.Errorf("stats are %+v, want 1 created", stats)
+			}
+			if n := len(cs.Events(testCalendar)); n != 1 {
+				t.Errorf("calendar has %d events, want 1", n)
+			}
+			if n := countRequests(cs, "insert"); n != 2 {
+				t.Errorf("sent %d inserts, want 2", n)
+			}
+		})
+	}
+}
+
+func TestWriteServerErrorBackoff(t *testing.T) {
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	ctx := context.Background()
+	w := testWriter(cs)
+	w.backoff = 20 * time.Millisecond
+	event := cs.AddEvent(testCalendar, testEvent("Flight UA 100"))
+
+	// The waits after the two errors are 20ms and 40ms.
+	cs.Fail("patch", http.StatusBadGateway, 2)
+	start := time.Now()
+	err := w.do(ctx, testCalendar, calendarWrite{op: writePatch, id: event.Id, event: &calendar.Event{Summary: "Flight UA 200"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 60*time.Millisecond {
+		t.Errorf("write took %s, want at least the backoff of 60ms", d)
+	}
+	if n := countRequests(cs, "patch"); n != 3 {
+		t.Errorf("sent %d patches, want 3", n)
+	}
+}
+
+func TestWriteGivesUp(t *testing.T) {
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	ctx := context.Background()
+	event := cs.AddEvent(testCalendar, testEvent("Flight UA 100"))
+
+	cs.Fail("delete", http.StatusServiceUnavailable, -1)
+	if err := testWriter(cs).do(ctx, testCalendar, calendarWrite{op: writeDelete, id: event.Id}); err == nil {
+		t.Fatal("expected the delete to fail")
+	}
+	if n := countRequests(cs, "delete"); n != maxWriteAttempts {
+		t.Errorf("sent %d deletes, want %d", n, maxWriteAttempts)
+	}
+}
+
+func TestWriteClientErrorIsNotRetried(t *testing.T) {
+	cs := calendartest.NewServer()
+	defer cs.Close()
+
+	ctx := context.Background()
+	if err := testWriter(cs).do(ctx, testCalendar, calendarWrite{op: writePatch, id: "missing", event: &calendar.Event{Summary: "Flight UA 200"}}); err == nil {
+		t.Fatal("expected the patch of a missing event to fail")
+	}
+	if n := countRequests(cs, "patch"); n != 1 {
+		t.Errorf("sent %d patches, want 1", n)
+	}
+}
+
+func TestWriteDeleteGone(t *testing.T) {
+	tests := []struct {
+		name string
+		// fail injects the faults into the calendar, it returns the ID of
+		// the event to delete.
+		fail func(cs *calendartest.Server) string
+		// want is how many deletes are sent.
+		want int
+	}{
+		{
+			name: "missing",
+			fail: func(cs *calendartest.Server) string {
+				return "missing"
+			},
+			want: 1,
+		},
+		{
+			name: "deleted by the user",
+			fail: func(cs *calendartest.Server) string {
+				deleted := testEvent("Flight UA 100")
+				deleted.Status = "cancelled"
+				return cs.AddEvent(testCalendar, deleted).Id
+			},
+			want: 1,
+		},
+		{
+			// The delete is done, but the response is lost, so the attempt
+			// after it finds the event deleted.
+			name: "server error then gone",
+			fail: func(cs *calendartest.Server) string {
+				event := cs.AddEvent(testCalendar, testEvent("Flight UA 100"))
+				cs.FailAfter("delete", http.StatusInternalServerError, 1)
+				return event.Id
+			},
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := calendartest.NewServer()
+			defer cs.Close()
+
+			ctx := context.Background()
+			id := tt.fail(cs)
+			stats, err := testWriter(cs).write(ctx, ctx, testCalendar, []calendarWrite{{op: writeDelete, id: id, title: "Flight UA 100"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.Deleted != 1 || stats.Failed != 0 {
+				t.Errorf("stats are %+v, want 1 deleted", stats)
+			}
+			if n := countRequests(cs, "delete"); n != tt.want {
+				t.Errorf("sent %d deletes, want %d", n, tt.want)
+			}
+		})
+	}
+}